@@ -2,21 +2,28 @@ package main
 
 import (
 	"context"
-	"log/slog"
 	"os/signal"
 	"syscall"
 
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/app"
-	cache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/logger"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/outbox"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres"
 	orderRepopository "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/repositories/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
 	loadWorker "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/worker"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/worker/job"
 	"github.com/D1sordxr/wb-tech-l0/internal/service/order"
 	"github.com/D1sordxr/wb-tech-l0/internal/transport/http"
+	cacheHandler "github.com/D1sordxr/wb-tech-l0/internal/transport/http/cache/handler"
+	loggingHandler "github.com/D1sordxr/wb-tech-l0/internal/transport/http/logging/handler"
 	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/order/handler"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dlq"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/invalidation"
 	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/reader"
 )
 
@@ -26,57 +33,113 @@ func main() {
 
 	cfg := config.NewConfig()
 
-	log := slog.Default()
+	log := logger.New(&cfg.Logging)
+
+	tracerProvider := tracing.NewProvider(log, &cfg.Tracing)
+	appMetrics := metrics.New()
 
 	pool := postgres.NewPool(ctx, &cfg.Storage)
-	orderRepo := orderRepopository.NewOrderRepo(pool)
+	appMetrics.RegisterPool(pool.Pool)
+	orderRepo := orderRepopository.NewOrderRepo(pool, appMetrics)
 
-	orderCache := cache.NewCache(log, orderRepo)
+	orderCache := cache.NewOrderCache(log, &cfg.Cache, orderRepo)
+	appMetrics.RegisterCache(orderCache.Stats)
 
-	orderReaderConn := kafka.NewReader(
+	orderConsumerConn, err := kafka.NewGroupConsumer(
 		log,
 		&cfg.MessageBroker,
 		cfg.MessageBroker.SaverGroup,
+		cfg.MessageBroker.OrdersTopic,
 	)
+	if err != nil {
+		panic(err)
+	}
 	orderWriterConn := kafka.NewWriter(log, &cfg.MessageBroker)
+	invalidationConsumerConn, err := kafka.NewBroadcastConsumer(
+		log,
+		&cfg.MessageBroker,
+		cfg.MessageBroker.InvalidationTopic,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	invalidationPublisher := invalidation.NewPublisher(log, orderWriterConn, &cfg.MessageBroker)
+
+	conflictPolicy, err := order.ParseConflictPolicy(cfg.Ingestion.ConflictPolicy)
+	if err != nil {
+		panic(err)
+	}
 
 	orderUseCase := order.NewUseCase(
 		log,
 		orderRepo,
 		orderCache,
+		invalidationPublisher,
+		conflictPolicy,
 	)
 
 	orderHandler := handler.NewHandler(orderUseCase)
+	cacheAdminHandler := cacheHandler.NewHandler(orderCache, orderUseCase, invalidationPublisher)
+	logAdminHandler := loggingHandler.NewHandler(log)
+
+	appHealth := app.NewHealth()
 
 	httpServer := http.NewServer(
 		log,
 		&cfg.Server,
+		appMetrics,
+		appHealth,
+		[]http.AdminHandler{orderHandler, cacheAdminHandler, logAdminHandler},
 		orderHandler,
 	)
 
+	orderDLQPublisher := dlq.NewPublisher(log, orderWriterConn)
+
 	orderKafkaReader := reader.NewReader(
 		log,
-		orderReaderConn,
+		orderConsumerConn,
 		orderUseCase,
-		cfg.MessageBroker.OrdersTopic,
+		orderDLQPublisher,
+		appMetrics,
+		&cfg.MessageBroker,
 	)
 
-	orderKafkaWriter := job.NewMockOrderWriter(log, orderWriterConn)
+	invalidationListener := invalidation.NewListener(
+		log,
+		invalidationConsumerConn,
+		orderUseCase,
+		&cfg.MessageBroker,
+	)
 
-	worker := loadWorker.NewWorker(
+	orderOutboxRelay := outbox.NewRelay(
 		log,
-		orderKafkaReader,
-		orderKafkaWriter,
+		&cfg.Outbox,
+		orderRepo,
+		orderWriterConn,
 	)
 
+	workerHandlers := []loadWorker.Handlers{orderKafkaReader, invalidationListener}
+	if cfg.LoadGenerator.Enabled {
+		workerHandlers = append(workerHandlers, job.NewMockOrderWriter(log, orderWriterConn, &cfg.LoadGenerator))
+	}
+
+	worker := loadWorker.NewWorker(log, workerHandlers...)
+
 	appContainer := app.NewApp(
 		log,
-		pool,
-		orderCache,
-		orderReaderConn,
-		orderWriterConn,
-		httpServer,
-		worker,
+		&cfg.Supervisor,
+		appMetrics,
+		appHealth,
+		app.Named("tracing", tracerProvider),
+		app.Named("postgres", pool),
+		app.Named("cache", orderCache),
+		app.Named("orders-consumer", orderConsumerConn),
+		app.Named("invalidation-consumer", invalidationConsumerConn),
+		app.Named("kafka-writer", orderWriterConn),
+		app.Named("outbox-relay", orderOutboxRelay),
+		app.Named("http", httpServer),
+		app.Named("worker", worker),
 	)
 	appContainer.Run(ctx)
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/logger"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dlq"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run redrives the dead-letter topic and returns the process exit code. Exiting only
+// after it returns lets the deferred shutdowns flush the writer and close the reader.
+func run() int {
+	group := flag.String("group", "dlq-redrive-group", "consumer group used to read the dead-letter topic")
+	limit := flag.Int("limit", 0, "maximum number of messages to redrive, 0 means all")
+	idle := flag.Duration("idle", 10*time.Second, "stop after the dead-letter topic stays idle this long")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.NewConfig()
+
+	log := logger.New(&cfg.Logging)
+
+	dlqReaderConn := kafka.NewTopicReader(
+		&cfg.MessageBroker,
+		*group,
+		cfg.MessageBroker.DLQTopic,
+	)
+	defer func() { _ = dlqReaderConn.Shutdown(context.Background()) }()
+
+	orderWriterConn := kafka.NewWriter(log, &cfg.MessageBroker)
+	defer func() { _ = orderWriterConn.Shutdown(context.Background()) }()
+
+	redriver := dlq.NewRedriver(log, dlqReaderConn, orderWriterConn)
+
+	log.Info("Redriving dead-letter messages",
+		"from", cfg.MessageBroker.DLQTopic,
+		"to", cfg.MessageBroker.OrdersTopic,
+		"limit", *limit,
+	)
+
+	redriven, err := redriver.Redrive(ctx, *limit, *idle)
+	if err != nil {
+		log.Error("Redrive failed", "redriven", redriven, "error", err.Error())
+		return 1
+	}
+
+	log.Info("Redrive finished", "redriven", redriven)
+	return 0
+}
@@ -0,0 +1,68 @@
+package ports
+
+import "context"
+
+type HealthStatus string
+
+const (
+	// HealthUp means the component is live and ready to serve.
+	HealthUp HealthStatus = "up"
+	// HealthNotReady means the component is live but should not receive traffic yet,
+	// e.g. it is still starting or a dependency is temporarily unreachable.
+	HealthNotReady HealthStatus = "not_ready"
+	// HealthDown means the component cannot recover without a restart.
+	HealthDown HealthStatus = "down"
+)
+
+type Health struct {
+	Status HealthStatus `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+}
+
+func Up() Health {
+	return Health{Status: HealthUp}
+}
+
+func NotReady(detail string) Health {
+	return Health{Status: HealthNotReady, Detail: detail}
+}
+
+func Down(detail string) Health {
+	return Health{Status: HealthDown, Detail: detail}
+}
+
+func (h Health) Live() bool {
+	return h.Status != HealthDown
+}
+
+func (h Health) Ready() bool {
+	return h.Status == HealthUp
+}
+
+// HealthChecker is implemented by components that can report their own health.
+// HealthCheck is called on every probe, so it must be cheap and respect ctx.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) Health
+}
+
+type ComponentHealth struct {
+	Name           string `json:"name"`
+	Critical       bool   `json:"critical"`
+	GatesReadiness bool   `json:"gates_readiness"`
+	Health
+}
+
+// HealthReport is live as its critical components are and ready as the components
+// that gate readiness are. Degraded means another component is not up while the app
+// keeps serving without it.
+type HealthReport struct {
+	Live       bool              `json:"live"`
+	Ready      bool              `json:"ready"`
+	Degraded   bool              `json:"degraded"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// HealthReporter reports the health of every component of the application.
+type HealthReporter interface {
+	HealthReport(ctx context.Context) HealthReport
+}
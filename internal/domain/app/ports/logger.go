@@ -1,8 +1,21 @@
 package ports
 
+import "context"
+
 type Logger interface {
 	Info(msg string, args ...any)
 	Debug(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+	// The Context variants also log the correlation ID and fields carried by ctx.
+	InfoContext(ctx context.Context, msg string, args ...any)
+	DebugContext(ctx context.Context, msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+// LogLevel controls the minimum level of the service logger at runtime.
+type LogLevel interface {
+	Level() string
+	SetLevel(level string) error
 }
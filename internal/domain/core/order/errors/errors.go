@@ -5,4 +5,10 @@ import "errors"
 var (
 	ErrOrderAlreadyExists = errors.New("order already exists")
 	ErrOrderNotFount      = errors.New("order not found")
+	ErrOrderConflict      = errors.New("order already exists with a different payload")
+	ErrCustomerNotFound   = errors.New("customer has no orders")
+	ErrInvalidPageLimit   = errors.New("page limit must be between 1 and 100")
+	ErrInvalidDateRange   = errors.New("date range start must be before its end")
+	// ErrStorageUnavailable marks transient storage failures that may succeed on retry.
+	ErrStorageUnavailable = errors.New("storage is temporarily unavailable")
 )
@@ -0,0 +1,27 @@
+package model
+
+type CacheStats struct {
+	Policy                string  `json:"policy"`
+	Entries               int     `json:"entries"`
+	Bytes                 int64   `json:"bytes"`
+	MaxEntries            int     `json:"max_entries"`
+	MaxBytes              int64   `json:"max_bytes"`
+	Hits                  int64   `json:"hits"`
+	Misses                int64   `json:"misses"`
+	HitRatio              float64 `json:"hit_ratio"`
+	Evictions             int64   `json:"evictions"`
+	EvictedBytes          int64   `json:"evicted_bytes"`
+	Expirations           int64   `json:"expirations"`
+	Missing               int     `json:"missing"`
+	MissingHits           int64   `json:"missing_hits"`
+	OldestEntryAgeSeconds float64 `json:"oldest_entry_age_seconds"`
+	// Tiers holds the stats of every tier of a layered cache, keyed by tier name.
+	Tiers map[string]CacheStats `json:"tiers,omitempty"`
+}
+
+func HitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+	EventOrderCreated = "OrderCreated"
+	EventOrderUpdated = "OrderUpdated"
+)
+
+type OutboxEvent struct {
+	ID          int64
+	AggregateID string
+	EventType   string
+	Payload     []byte
+	CreatedAt   time.Time
+	// TraceParent is the W3C trace context of the request that stored the event.
+	TraceParent string
+}
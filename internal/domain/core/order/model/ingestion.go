@@ -0,0 +1,9 @@
+package model
+
+type IngestionStats struct {
+	Created    int64 `json:"created"`
+	Duplicates int64 `json:"duplicates"`
+	Updated    int64 `json:"updated"`
+	Stale      int64 `json:"stale"`
+	Conflicts  int64 `json:"conflicts"`
+}
@@ -0,0 +1,17 @@
+package model
+
+type InvalidationAction string
+
+const (
+	// InvalidationEvict drops the order from the cache; the next read loads it from storage.
+	InvalidationEvict InvalidationAction = "evict"
+	// InvalidationRefresh reloads the order from storage right away.
+	InvalidationRefresh InvalidationAction = "refresh"
+	// InvalidationCreated tells replicas a new order exists, so they stop treating it as missing.
+	InvalidationCreated InvalidationAction = "created"
+)
+
+type CacheInvalidation struct {
+	OrderUID string
+	Action   InvalidationAction
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type OrderCursor struct {
+	DateCreated time.Time
+	OrderUID    string
+}
+
+type OrderFilter struct {
+	CustomerID      string
+	TrackNumber     string
+	DeliveryService string
+	Locale          string
+	Currency        string
+	Provider        string
+	Bank            string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	Ascending       bool
+	Cursor          *OrderCursor
+	Limit           int
+}
+
+type OrderPage struct {
+	Orders     []*Order
+	NextCursor *OrderCursor
+}
+
+type CustomerSummary struct {
+	CustomerID      string           `json:"customer_id"`
+	OrderCount      int64            `json:"order_count"`
+	SpendByCurrency map[string]int64 `json:"spend_by_currency"`
+	FirstOrderAt    time.Time        `json:"first_order_at"`
+	LastOrderAt     time.Time        `json:"last_order_at"`
+}
+
+type CustomerOrders struct {
+	Summary *CustomerSummary
+	Page    *OrderPage
+}
@@ -1,8 +1,40 @@
 package ports
 
-import "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+import (
+	"context"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
 
 type OrderCache interface {
 	Set(orderUID string, order *model.Order)
 	Get(orderUID string) *model.Order
+	// GetStale returns an expired order that is still within the stale-while-revalidate
+	// window, or nil if there is none or the window is disabled.
+	GetStale(orderUID string) *model.Order
+	// Peek returns the cached order, fresh or expired, without counting a hit or
+	// a miss and without touching its eviction order.
+	Peek(orderUID string) *model.Order
+	// SetMissing remembers that the order does not exist in storage.
+	// A later Set of the same UID forgets it.
+	SetMissing(orderUID string)
+	IsMissing(orderUID string) bool
+	// Delete drops the order and reports whether it was cached.
+	Delete(orderUID string) bool
+	SetIndex(key string, orders []*model.Order)
+	GetIndex(key string) []*model.Order
+	DeleteIndex(keys ...string)
+}
+
+type CacheAdmin interface {
+	Stats() model.CacheStats
+	Keys() []string
+	Delete(orderUID string) bool
+	Flush() int
+	WarmUp(ctx context.Context, limit int) (int, error)
+}
+
+// CacheInvalidator tells the other replicas that a cached order is out of date.
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, invalidations ...model.CacheInvalidation) error
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+type OutboxRelayRepo interface {
+	// ClaimOutboxEvents leases up to limit of the oldest pending events to owner, in id
+	// order. It returns none while another owner holds a live lease, so only one relay
+	// publishes at a time.
+	ClaimOutboxEvents(ctx context.Context, owner string, limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	// MarkOutboxEventsSent confirms that the events were published.
+	MarkOutboxEventsSent(ctx context.Context, ids []int64) error
+}
@@ -9,8 +9,16 @@ import (
 type OrderRepo interface {
 	GetOrder(ctx context.Context, orderID string) (*model.Order, error)
 	CreateOrder(ctx context.Context, order *model.Order) error
+	CreateOrders(ctx context.Context, orders []*model.Order) ([]error, error)
+	UpdateOrder(ctx context.Context, order *model.Order, onlyIfNewer bool) (bool, error)
+	ListOrders(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error)
+	GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error)
+	GetOrdersByTransactionID(ctx context.Context, transactionID string) ([]*model.Order, error)
+	GetCustomerSummary(ctx context.Context, customerID string) (*model.CustomerSummary, error)
 }
 
 type CacheInitializer interface {
 	GetOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error)
+	GetMostReadOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error)
+	RecordOrderReads(ctx context.Context, reads map[string]int64) error
 }
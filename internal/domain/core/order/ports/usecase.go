@@ -9,5 +9,16 @@ import (
 
 type UseCase interface {
 	CreateOrder(ctx context.Context, orderDTO dto.Order) error
+	// SubmitOrder creates an order sent by an API client. Unlike CreateOrder it never
+	// applies the ingestion conflict policy: an existing order is ErrOrderAlreadyExists.
+	SubmitOrder(ctx context.Context, orderDTO dto.Order) error
+	CreateOrders(ctx context.Context, orderDTOs []dto.Order) ([]error, error)
 	GetByID(ctx context.Context, orderID string) (*model.Order, error)
+	ListOrders(ctx context.Context, filter model.OrderFilter) (*model.OrderPage, error)
+	GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error)
+	GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Order, error)
+	GetCustomerOrders(ctx context.Context, customerID string, filter model.OrderFilter) (*model.CustomerOrders, error)
+	IngestionStats() model.IngestionStats
+	// ApplyInvalidation brings the locally cached copy of an order up to date.
+	ApplyInvalidation(ctx context.Context, invalidation model.CacheInvalidation) error
 }
@@ -4,30 +4,83 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
 
 	"golang.org/x/sync/errgroup"
 )
 
+// appComponent is run for the lifetime of the app. A component may also implement
+// ports.HealthChecker to report its own liveness and readiness while it runs.
+// Run may be called again after it returns, when the component is restarted.
 type appComponent interface {
 	Run(ctx context.Context) error
 	Shutdown(ctx context.Context) error
 }
 
+// Component is an app component under the name it has in logs, metrics,
+// health reports and the supervisor config.
+type Component struct {
+	name      string
+	component appComponent
+}
+
+func Named(name string, component appComponent) Component {
+	return Component{name: name, component: component}
+}
+
 type App struct {
-	log        ports.Logger
-	components []appComponent
+	log         ports.Logger
+	health      *Health
+	supervisors []*supervisor
 }
 
 func NewApp(
 	log ports.Logger,
-	components ...appComponent,
+	cfg *config.Supervisor,
+	metrics *metrics.Metrics,
+	health *Health,
+	components ...Component,
 ) *App {
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		if slices.Contains(names, c.name) {
+			panic(fmt.Sprintf("app component %q is registered twice", c.name))
+		}
+		names = append(names, c.name)
+	}
+	for _, name := range cfg.Critical {
+		if !slices.Contains(names, name) {
+			panic(fmt.Sprintf("supervisor config: unknown critical component %q", name))
+		}
+	}
+	for _, name := range cfg.Readiness {
+		if !slices.Contains(names, name) {
+			panic(fmt.Sprintf("supervisor config: unknown readiness component %q", name))
+		}
+	}
+	for name := range cfg.Components {
+		if !slices.Contains(names, name) {
+			panic(fmt.Sprintf("supervisor config: unknown component %q", name))
+		}
+	}
+
+	supervisors := make([]*supervisor, 0, len(components))
+	for _, c := range components {
+		critical := slices.Contains(cfg.Critical, c.name)
+		gatesReadiness := critical || slices.Contains(cfg.Readiness, c.name)
+		idx := health.add(c.name, c.component, critical, gatesReadiness)
+		supervisors = append(supervisors, newSupervisor(log, metrics, health, idx, c, cfg, critical))
+	}
+
 	return &App{
-		log:        log,
-		components: components,
+		log:         log,
+		health:      health,
+		supervisors: supervisors,
 	}
 }
 
@@ -36,26 +89,23 @@ func (a *App) Run(ctx context.Context) {
 
 	errChan := make(chan error)
 	errGroup, ctx := errgroup.WithContext(ctx)
-	go func() { errChan <- errGroup.Wait() }()
+	context.AfterFunc(ctx, a.health.setStopping)
 
-	for i, c := range a.components {
-		component := c
-		idx := i
+	for _, s := range a.supervisors {
 		errGroup.Go(func() error {
-			a.log.Info("Starting appComponent", "idx", idx, "type", fmt.Sprintf("%T", component))
-			err := component.Run(ctx)
-			if err != nil {
-				a.log.Error("Component failed", "idx", idx, "type", fmt.Sprintf("%T", component), "error", err)
-			} else {
-				a.log.Info("Component stopped", "idx", idx, "type", fmt.Sprintf("%T", component))
-			}
-			return err
+			return s.run(ctx)
 		})
 	}
+	// Waited on only once every supervisor is in the group, or Wait may return early.
+	go func() { errChan <- errGroup.Wait() }()
 
 	select {
 	case err := <-errChan:
-		a.log.Error("App received an error", "error", err.Error())
+		if err != nil {
+			a.log.Error("App received an error", "error", err.Error())
+		} else {
+			a.log.Warn("All app components stopped")
+		}
 	case <-ctx.Done():
 		a.log.Info("App received a terminate signal")
 	}
@@ -67,10 +117,11 @@ func (a *App) shutdown() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	errs := make([]error, 0, len(a.components))
-	for i := len(a.components) - 1; i >= 0; i-- {
-		a.log.Info("Shutting down appComponent", "idx", i)
-		if err := a.components[i].Shutdown(shutdownCtx); err != nil {
+	errs := make([]error, 0, len(a.supervisors))
+	for i := len(a.supervisors) - 1; i >= 0; i-- {
+		s := a.supervisors[i]
+		a.log.Info("Shutting down appComponent", "idx", i, "component", s.name)
+		if err := s.component.Shutdown(shutdownCtx); err != nil {
 			errs = append(errs, err)
 		}
 	}
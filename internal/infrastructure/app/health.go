@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+)
+
+type runState int
+
+const (
+	stateStarting runState = iota
+	stateRunning
+	stateRestarting
+	stateStopped
+	stateFailed
+)
+
+type componentState struct {
+	name           string
+	component      appComponent
+	critical       bool
+	gatesReadiness bool
+	state          runState
+	err            error
+	restarts       int
+}
+
+// Health tracks whether the components of the app are running and combines that
+// with the health the components report themselves. It is created before the app,
+// so the HTTP server can serve it while being one of the components.
+type Health struct {
+	mu       sync.RWMutex
+	states   []componentState
+	stopping bool
+}
+
+func NewHealth() *Health {
+	return &Health{}
+}
+
+func (h *Health) add(name string, component appComponent, critical, gatesReadiness bool) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.states = append(h.states, componentState{
+		name:           name,
+		component:      component,
+		critical:       critical,
+		gatesReadiness: gatesReadiness,
+	})
+	return len(h.states) - 1
+}
+
+func (h *Health) setRunning(idx int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.states[idx].state = stateRunning
+}
+
+func (h *Health) setRestarting(idx int, err error, restarts int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.states[idx].state = stateRestarting
+	h.states[idx].err = err
+	h.states[idx].restarts = restarts
+}
+
+func (h *Health) setExited(idx int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.states[idx].state = stateFailed
+	} else {
+		h.states[idx].state = stateStopped
+	}
+	h.states[idx].err = err
+}
+
+// setStopping makes the app report not ready, so traffic is drained before shutdown.
+func (h *Health) setStopping() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopping = true
+}
+
+// HealthReport is live unless a critical component is down and ready only when every
+// component that gates readiness is up, critical ones included. Other components that
+// are not up only mark the app as degraded. Components that implement
+// ports.HealthChecker are asked only while they run.
+func (h *Health) HealthReport(ctx context.Context) ports.HealthReport {
+	h.mu.RLock()
+	states := append([]componentState(nil), h.states...)
+	stopping := h.stopping
+	h.mu.RUnlock()
+
+	report := ports.HealthReport{
+		Live:       true,
+		Ready:      true,
+		Components: make([]ports.ComponentHealth, 0, len(states)),
+	}
+
+	for _, state := range states {
+		health := componentHealth(ctx, state, stopping)
+
+		if state.critical {
+			report.Live = report.Live && health.Live()
+		}
+		if state.gatesReadiness {
+			report.Ready = report.Ready && health.Ready()
+		} else if !health.Ready() {
+			report.Degraded = true
+		}
+		report.Components = append(report.Components, ports.ComponentHealth{
+			Name:           state.name,
+			Critical:       state.critical,
+			GatesReadiness: state.gatesReadiness,
+			Health:         health,
+		})
+	}
+
+	return report
+}
+
+func componentHealth(ctx context.Context, state componentState, stopping bool) ports.Health {
+	switch {
+	case state.state == stateFailed:
+		return ports.Down(state.err.Error())
+	case stopping:
+		return ports.NotReady("shutting down")
+	case state.state == stateStarting:
+		return ports.NotReady("starting")
+	case state.state == stateRestarting:
+		reason := "stopped"
+		if state.err != nil {
+			reason = state.err.Error()
+		}
+		return ports.NotReady(fmt.Sprintf("restarting (attempt %d) after: %s", state.restarts, reason))
+	case state.state == stateStopped:
+		return ports.Down("stopped")
+	}
+
+	if checker, ok := state.component.(ports.HealthChecker); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return ports.Up()
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+)
+
+type restartPolicy string
+
+const (
+	restartNever     restartPolicy = "never"
+	restartOnFailure restartPolicy = "on-failure"
+	restartAlways    restartPolicy = "always"
+)
+
+// errExitedEarly is the failure of a component that returned without an error
+// while the app was still running, unless its policy is always. Components are
+// meant to run until ctx is done.
+var errExitedEarly = errors.New("component returned before the app stopped")
+
+// supervisor runs one component and restarts it according to its policy
+// until the restart budget runs out.
+type supervisor struct {
+	log       ports.Logger
+	metrics   *metrics.Metrics
+	health    *Health
+	idx       int
+	name      string
+	component appComponent
+
+	policy      restartPolicy
+	maxRestarts int
+	critical    bool
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	resetAfter  time.Duration
+}
+
+func newSupervisor(
+	log ports.Logger,
+	metrics *metrics.Metrics,
+	health *Health,
+	idx int,
+	c Component,
+	cfg *config.Supervisor,
+	critical bool,
+) *supervisor {
+	s := &supervisor{
+		log:         log,
+		metrics:     metrics,
+		health:      health,
+		idx:         idx,
+		name:        c.name,
+		component:   c.component,
+		policy:      restartPolicy(cfg.Restart),
+		maxRestarts: cfg.MaxRestarts,
+		critical:    critical,
+		backoff:     cfg.Backoff,
+		maxBackoff:  cfg.MaxBackoff,
+		resetAfter:  cfg.ResetAfter,
+	}
+
+	if override, ok := cfg.Components[c.name]; ok {
+		if override.Restart != "" {
+			s.policy = restartPolicy(override.Restart)
+		}
+		if override.MaxRestarts != nil {
+			s.maxRestarts = *override.MaxRestarts
+		}
+	}
+
+	return s
+}
+
+// run returns an error only when a critical component fails for good, which stops the app.
+// A non-critical component that gives up is left down while the rest of the app keeps running.
+func (s *supervisor) run(ctx context.Context) error {
+	const op = "app.supervisor.run"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "component", s.name, "critical", s.critical}, args...)
+	}
+
+	restarts := 0
+	for {
+		s.log.Info("Starting appComponent", withFields("restarts", restarts)...)
+		s.health.setRunning(s.idx)
+		s.metrics.ComponentStarted(s.name)
+
+		started := time.Now()
+		err := s.component.Run(ctx)
+		s.metrics.ComponentExited(s.name, err)
+
+		if ctx.Err() != nil {
+			s.health.setExited(s.idx, err)
+			s.log.Info("Component stopped", withFields()...)
+			return nil
+		}
+
+		// Under always a clean exit is restarted after the initial backoff
+		// and is not charged against the restart budget.
+		if err == nil && s.policy == restartAlways {
+			s.health.setRestarting(s.idx, nil, restarts)
+			s.log.Info("Component exited, restarting", withFields("backoff", s.backoff)...)
+			if !sleep(ctx, s.backoff) {
+				s.health.setExited(s.idx, nil)
+				return nil
+			}
+			s.metrics.ComponentRestarted(s.name)
+			continue
+		}
+
+		if err == nil {
+			err = errExitedEarly
+		}
+		s.log.Error("Component failed", withFields("error", err.Error())...)
+
+		if !s.shouldRestart() {
+			s.health.setExited(s.idx, err)
+			return s.giveUp(err, withFields)
+		}
+
+		// A component that ran long enough since the last restart gets its budget back.
+		if time.Since(started) >= s.resetAfter {
+			restarts = 0
+		}
+		if restarts >= s.maxRestarts {
+			s.log.Error("Component restart budget exhausted", withFields("max_restarts", s.maxRestarts)...)
+			s.health.setExited(s.idx, err)
+			return s.giveUp(err, withFields)
+		}
+
+		restarts++
+		backoff := s.backoffFor(restarts)
+		s.health.setRestarting(s.idx, err, restarts)
+		s.log.Warn("Restarting component",
+			withFields("attempt", restarts, "max_restarts", s.maxRestarts, "backoff", backoff)...,
+		)
+		if !sleep(ctx, backoff) {
+			s.health.setExited(s.idx, err)
+			return nil
+		}
+		s.metrics.ComponentRestarted(s.name)
+	}
+}
+
+// shouldRestart reports whether a component that failed is restarted. Both on-failure
+// and always restart failures; they differ only on clean exits.
+func (s *supervisor) shouldRestart() bool {
+	return s.policy == restartOnFailure || s.policy == restartAlways
+}
+
+func (s *supervisor) giveUp(err error, withFields func(args ...any) []any) error {
+	if !s.critical {
+		s.log.Error("Non-critical component is down, the app keeps running without it", withFields()...)
+		return nil
+	}
+	return fmt.Errorf("%s: %w", s.name, err)
+}
+
+// backoffFor doubles the initial backoff with every restart in a row, up to maxBackoff.
+func (s *supervisor) backoffFor(restart int) time.Duration {
+	backoff := s.backoff
+	for i := 1; i < restart && backoff < s.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.maxBackoff)
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
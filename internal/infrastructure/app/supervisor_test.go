@@ -0,0 +1,262 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+)
+
+var errBoom = errors.New("boom")
+
+// scripted returns the scripted results of its first runs, then runs until ctx is done.
+type scripted struct {
+	results []error
+	runs    atomic.Int64
+	started chan struct{}
+}
+
+func newScripted(results ...error) *scripted {
+	return &scripted{results: results, started: make(chan struct{}, 100)}
+}
+
+func (c *scripted) Run(ctx context.Context) error {
+	run := int(c.runs.Add(1))
+	c.started <- struct{}{}
+	if run <= len(c.results) {
+		return c.results[run-1]
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (c *scripted) Shutdown(context.Context) error {
+	return nil
+}
+
+func testSupervisorConfig(restart string, maxRestarts int) *config.Supervisor {
+	return &config.Supervisor{
+		Restart:     restart,
+		MaxRestarts: maxRestarts,
+		Backoff:     time.Millisecond,
+		MaxBackoff:  4 * time.Millisecond,
+		ResetAfter:  time.Hour,
+	}
+}
+
+func newTestSupervisor(cfg *config.Supervisor, critical bool, component appComponent) (*supervisor, *Health) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	health := NewHealth()
+	c := Named("test", component)
+	idx := health.add(c.name, c.component, critical, critical)
+
+	return newSupervisor(log, metrics.New(), health, idx, c, cfg, critical), health
+}
+
+// runUntilStarted runs the supervisor until the component has started runs times,
+// then stops it and returns what run returned.
+func runUntilStarted(t *testing.T, s *supervisor, component *scripted, runs int) error {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- s.run(ctx) }()
+
+	for range runs {
+		select {
+		case <-component.started:
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatalf("component started %d times, want %d", component.runs.Load(), runs)
+		}
+	}
+	cancel()
+
+	return <-done
+}
+
+func TestSupervisorRestartsOnFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []error
+	}{
+		{name: "error", results: []error{errBoom, errBoom}},
+		{name: "early return", results: []error{nil, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := newScripted(tt.results...)
+			s, _ := newTestSupervisor(testSupervisorConfig("on-failure", 5), true, component)
+
+			if err := runUntilStarted(t, s, component, len(tt.results)+1); err != nil {
+				t.Fatalf("run = %v, want nil", err)
+			}
+			if runs := component.runs.Load(); runs != int64(len(tt.results)+1) {
+				t.Fatalf("runs = %d, want %d", runs, len(tt.results)+1)
+			}
+		})
+	}
+}
+
+func TestSupervisorNeverRestarts(t *testing.T) {
+	component := newScripted(nil)
+	s, health := newTestSupervisor(testSupervisorConfig("never", 5), true, component)
+
+	err := s.run(context.Background())
+	if !errors.Is(err, errExitedEarly) {
+		t.Fatalf("run = %v, want %v", err, errExitedEarly)
+	}
+	if runs := component.runs.Load(); runs != 1 {
+		t.Fatalf("runs = %d, want 1", runs)
+	}
+	if report := health.HealthReport(context.Background()); report.Live {
+		t.Fatal("app is live after a critical component stopped")
+	}
+}
+
+func TestSupervisorAlwaysRestartsCleanExits(t *testing.T) {
+	// No budget at all: clean exits must not be charged against it.
+	component := newScripted(nil, nil, nil)
+	s, _ := newTestSupervisor(testSupervisorConfig("always", 0), true, component)
+
+	if err := runUntilStarted(t, s, component, 4); err != nil {
+		t.Fatalf("run = %v, want nil", err)
+	}
+}
+
+func TestSupervisorAlwaysChargesFailures(t *testing.T) {
+	component := newScripted(nil, errBoom)
+	s, _ := newTestSupervisor(testSupervisorConfig("always", 0), true, component)
+
+	if err := s.run(context.Background()); !errors.Is(err, errBoom) {
+		t.Fatalf("run = %v, want %v", err, errBoom)
+	}
+	if runs := component.runs.Load(); runs != 2 {
+		t.Fatalf("runs = %d, want 2", runs)
+	}
+}
+
+func TestSupervisorOverrideDisablesRestarts(t *testing.T) {
+	noRestarts := 0
+	cfg := testSupervisorConfig("on-failure", 5)
+	cfg.Components = map[string]config.SupervisedComponent{
+		"test": {MaxRestarts: &noRestarts},
+	}
+
+	component := newScripted(errBoom)
+	s, _ := newTestSupervisor(cfg, true, component)
+
+	if err := s.run(context.Background()); !errors.Is(err, errBoom) {
+		t.Fatalf("run = %v, want %v", err, errBoom)
+	}
+	if runs := component.runs.Load(); runs != 1 {
+		t.Fatalf("runs = %d, want 1", runs)
+	}
+}
+
+func TestSupervisorBudgetExhausted(t *testing.T) {
+	const maxRestarts = 3
+
+	tests := []struct {
+		name     string
+		critical bool
+		wantErr  error
+	}{
+		{name: "critical", critical: true, wantErr: errBoom},
+		{name: "non-critical", critical: false, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make([]error, maxRestarts+1)
+			for i := range results {
+				results[i] = errBoom
+			}
+			component := newScripted(results...)
+			s, health := newTestSupervisor(testSupervisorConfig("on-failure", maxRestarts), tt.critical, component)
+
+			err := s.run(context.Background())
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("run = %v, want %v", err, tt.wantErr)
+			}
+			if runs := component.runs.Load(); runs != maxRestarts+1 {
+				t.Fatalf("runs = %d, want %d", runs, maxRestarts+1)
+			}
+
+			report := health.HealthReport(context.Background())
+			if report.Components[0].Health.Live() {
+				t.Fatal("component is live after giving up")
+			}
+		})
+	}
+}
+
+func TestSupervisorBudgetRefills(t *testing.T) {
+	cfg := testSupervisorConfig("on-failure", 1)
+	cfg.ResetAfter = time.Nanosecond
+
+	component := newScripted(errBoom, errBoom, errBoom, errBoom)
+	s, _ := newTestSupervisor(cfg, true, component)
+
+	if err := runUntilStarted(t, s, component, 5); err != nil {
+		t.Fatalf("run = %v, want nil", err)
+	}
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	s, _ := newTestSupervisor(&config.Supervisor{
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+	}, true, newScripted())
+
+	tests := []struct {
+		restart int
+		want    time.Duration
+	}{
+		{restart: 1, want: time.Second},
+		{restart: 2, want: 2 * time.Second},
+		{restart: 3, want: 4 * time.Second},
+		{restart: 4, want: 5 * time.Second},
+		{restart: 10, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := s.backoffFor(tt.restart); got != tt.want {
+			t.Errorf("backoffFor(%d) = %s, want %s", tt.restart, got, tt.want)
+		}
+	}
+}
+
+func TestSupervisorStopsWithContext(t *testing.T) {
+	component := newScripted(errBoom)
+	cfg := testSupervisorConfig("on-failure", 5)
+	cfg.Backoff = time.Hour
+	cfg.MaxBackoff = time.Hour
+	s, _ := newTestSupervisor(cfg, true, component)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.run(ctx) }()
+
+	<-component.started
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return during the backoff after ctx was done")
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	memoryCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	redisCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/redis/order"
+	tieredCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/tiered/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+)
+
+// OrderCache is an order cache that also runs as an app component.
+type OrderCache interface {
+	ports.OrderCache
+	ports.CacheAdmin
+	Run(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+}
+
+// NewOrderCache builds the cache backend selected in the config.
+func NewOrderCache(
+	log appPorts.Logger,
+	cfg *config.Cache,
+	initializer ports.CacheInitializer,
+) OrderCache {
+	switch cfg.Backend {
+	case "redis":
+		return redisCache.NewCache(log, cfg, redisCache.NewClient(&cfg.Redis), initializer)
+	case "tiered":
+		remote := redisCache.NewCache(log, cfg, redisCache.NewClient(&cfg.Redis), initializer)
+		return tieredCache.NewCache(
+			memoryCache.NewCache(log, cfg, tieredCache.LocalInitializer(remote, initializer)),
+			remote,
+		)
+	default:
+		return memoryCache.NewCache(log, cfg, initializer)
+	}
+}
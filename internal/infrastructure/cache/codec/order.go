@@ -0,0 +1,230 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+// Version is written as the first byte of every encoded order
+// and must be bumped whenever the layout changes.
+const Version byte = 1
+
+var (
+	ErrUnknownVersion = errors.New("unknown order encoding version")
+	ErrTruncated      = errors.New("truncated order encoding")
+)
+
+// AppendOrder appends the binary encoding of order to buf: strings are length-prefixed,
+// integers are varints, so a typical order takes a fraction of its JSON size.
+func AppendOrder(buf []byte, order *model.Order) []byte {
+	buf = append(buf, Version)
+
+	buf = appendString(buf, order.OrderUID)
+	buf = appendString(buf, order.TrackNumber)
+	buf = appendString(buf, order.Entry)
+	buf = appendString(buf, order.Locale)
+	buf = appendString(buf, order.InternalSignature)
+	buf = appendString(buf, order.CustomerID)
+	buf = appendString(buf, order.DeliveryService)
+	buf = appendString(buf, order.ShardKey)
+	buf = binary.AppendVarint(buf, int64(order.SmID))
+	buf = appendTime(buf, order.DateCreated)
+	buf = appendString(buf, order.OofShard)
+
+	delivery := order.Delivery
+	buf = appendString(buf, delivery.Name)
+	buf = appendString(buf, delivery.Phone)
+	buf = appendString(buf, delivery.Zip)
+	buf = appendString(buf, delivery.City)
+	buf = appendString(buf, delivery.Address)
+	buf = appendString(buf, delivery.Region)
+	buf = appendString(buf, delivery.Email)
+
+	payment := order.Payment
+	buf = appendString(buf, payment.Transaction)
+	buf = appendString(buf, payment.RequestID)
+	buf = appendString(buf, payment.Currency)
+	buf = appendString(buf, payment.Provider)
+	buf = binary.AppendVarint(buf, int64(payment.Amount))
+	buf = binary.AppendVarint(buf, payment.PaymentDt)
+	buf = appendString(buf, payment.Bank)
+	buf = binary.AppendVarint(buf, int64(payment.DeliveryCost))
+	buf = binary.AppendVarint(buf, int64(payment.GoodsTotal))
+	buf = binary.AppendVarint(buf, int64(payment.CustomFee))
+
+	buf = binary.AppendUvarint(buf, uint64(len(order.Items)))
+	for _, item := range order.Items {
+		buf = binary.AppendVarint(buf, item.ChrtID)
+		buf = appendString(buf, item.TrackNumber)
+		buf = binary.AppendVarint(buf, int64(item.Price))
+		buf = appendString(buf, item.RID)
+		buf = appendString(buf, item.Name)
+		buf = binary.AppendVarint(buf, int64(item.Sale))
+		buf = appendString(buf, item.Size)
+		buf = binary.AppendVarint(buf, int64(item.TotalPrice))
+		buf = binary.AppendVarint(buf, item.NmID)
+		buf = appendString(buf, item.Brand)
+		buf = binary.AppendVarint(buf, int64(item.Status))
+	}
+
+	return buf
+}
+
+func EncodeOrder(order *model.Order) []byte {
+	return AppendOrder(make([]byte, 0, 512), order)
+}
+
+// DecodeOrder decodes an order written by AppendOrder and reports how many bytes it used.
+func DecodeOrder(data []byte) (*model.Order, int, error) {
+	if len(data) == 0 {
+		return nil, 0, ErrTruncated
+	}
+	if data[0] != Version {
+		return nil, 0, fmt.Errorf("%w: %d", ErrUnknownVersion, data[0])
+	}
+
+	d := decoder{data: data, pos: 1}
+	order := &model.Order{}
+
+	order.OrderUID = d.string()
+	order.TrackNumber = d.string()
+	order.Entry = d.string()
+	order.Locale = d.string()
+	order.InternalSignature = d.string()
+	order.CustomerID = d.string()
+	order.DeliveryService = d.string()
+	order.ShardKey = d.string()
+	order.SmID = d.int32()
+	order.DateCreated = d.time()
+	order.OofShard = d.string()
+
+	order.Delivery = model.Delivery{
+		Name:    d.string(),
+		Phone:   d.string(),
+		Zip:     d.string(),
+		City:    d.string(),
+		Address: d.string(),
+		Region:  d.string(),
+		Email:   d.string(),
+	}
+
+	order.Payment = model.Payment{
+		Transaction:  d.string(),
+		RequestID:    d.string(),
+		Currency:     d.string(),
+		Provider:     d.string(),
+		Amount:       d.int32(),
+		PaymentDt:    d.varint(),
+		Bank:         d.string(),
+		DeliveryCost: d.int32(),
+		GoodsTotal:   d.int32(),
+		CustomFee:    d.int32(),
+	}
+
+	count := d.uvarint()
+	if d.err == nil && count > uint64(len(data)) {
+		d.err = ErrTruncated
+	}
+	if d.err == nil {
+		order.Items = make([]model.Item, count)
+		for i := range order.Items {
+			order.Items[i] = model.Item{
+				ChrtID:      d.varint(),
+				TrackNumber: d.string(),
+				Price:       d.int32(),
+				RID:         d.string(),
+				Name:        d.string(),
+				Sale:        d.int32(),
+				Size:        d.string(),
+				TotalPrice:  d.int32(),
+				NmID:        d.varint(),
+				Brand:       d.string(),
+				Status:      d.int32(),
+			}
+		}
+	}
+
+	if d.err != nil {
+		return nil, 0, d.err
+	}
+
+	return order, d.pos, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// appendTime writes t as nanoseconds since the epoch; zero stays zero
+// because the zero time is outside the range UnixNano can represent.
+func appendTime(buf []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return binary.AppendVarint(buf, 0)
+	}
+	return binary.AppendVarint(buf, t.UnixNano())
+}
+
+// decoder reads fields sequentially and remembers the first error,
+// so callers check it once at the end.
+type decoder struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		d.err = ErrTruncated
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		d.err = ErrTruncated
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) int32() int32 {
+	return int32(d.varint()) // #nosec G115 -- written from an int32
+}
+
+func (d *decoder) time() time.Time {
+	nanos := d.varint()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.data)-d.pos) {
+		d.err = ErrTruncated
+		return ""
+	}
+	s := string(d.data[d.pos : d.pos+int(n)])
+	d.pos += int(n)
+	return s
+}
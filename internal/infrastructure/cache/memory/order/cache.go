@@ -1,115 +1,505 @@
 package order
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"hash/maphash"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
 )
 
+// Cache keeps orders in memory, spread over independently locked shards picked by a
+// hash of the UID, so readers of different orders rarely wait for each other. The
+// eviction bounds are split between shards and enforced per shard.
 type Cache struct {
 	log         appPorts.Logger
-	mu          sync.RWMutex
-	store       map[string]*cacheItem
+	shards      []*shard
+	seed        maphash.Seed
+	mask        uint64
+	indexMu     sync.RWMutex
+	index       map[string]*indexItem
 	ttl         time.Duration
 	stopChan    chan struct{}
+	stopOnce    sync.Once
 	initializer ports.CacheInitializer
+
+	cleanupInterval time.Duration
+	staleWindow     time.Duration
+	negativeTTL     time.Duration
+	snapshotPath    string
+	snapshotEvery   time.Duration
+	snapshotOnce    sync.Once
+	warmUpCount     int
+	warmUpStrategy  WarmUpStrategy
+	warmedUp        atomic.Bool
+
+	policy     EvictionPolicy
+	maxEntries int
+	maxBytes   int64
 }
 
+// cacheCounters of a shard. The lookup counters are updated under the read lock,
+// the rest under the write lock.
+type cacheCounters struct {
+	hits         atomic.Int64
+	misses       atomic.Int64
+	missingHits  atomic.Int64
+	evictions    int64
+	evictedBytes int64
+	expirations  int64
+}
+
+type WarmUpStrategy string
+
 const (
-	ttl   = time.Minute * 5
-	limit = 100
+	WarmUpLatest   WarmUpStrategy = "latest"
+	WarmUpMostRead WarmUpStrategy = "most_read"
 )
 
 type cacheItem struct {
+	key       string
 	order     *model.Order
+	size      int64
 	expiresAt time.Time
+	elem      *list.Element
+	// reads counts lookups since the last flush of read counters.
+	reads atomic.Int64
 }
 
+type indexItem struct {
+	orderUIDs []string
+	expiresAt time.Time
+}
+
+// maxMissing bounds the negative entries when the cache has no entry bound of its own,
+// so probing random UIDs cannot grow it without limit.
+const maxMissing = 10000
+
 func NewCache(
 	log appPorts.Logger,
+	cfg *config.Cache,
 	initializer ports.CacheInitializer,
 ) *Cache {
+	policy, err := ParseEvictionPolicy(cfg.Eviction.Policy)
+	if err != nil {
+		panic(err)
+	}
+
+	count := shardsFor(shardCount(cfg.Shards), cfg.Eviction.MaxEntries)
+	missingLimit := maxMissing
+	if cfg.Eviction.MaxEntries > 0 {
+		missingLimit = cfg.Eviction.MaxEntries
+	}
+
+	shards := make([]*shard, count)
+	for i := range shards {
+		shards[i] = newShard(
+			policy,
+			perShard(cfg.Eviction.MaxEntries, count, i),
+			perShard(cfg.Eviction.MaxBytes, count, i),
+			perShard(missingLimit, count, i),
+		)
+	}
+
 	cache := &Cache{
 		log:         log,
-		store:       make(map[string]*cacheItem),
-		ttl:         ttl,
+		shards:      shards,
+		seed:        maphash.MakeSeed(),
+		mask:        uint64(count - 1),
+		index:       make(map[string]*indexItem),
+		ttl:         cfg.TTL,
 		stopChan:    make(chan struct{}),
 		initializer: initializer,
+
+		cleanupInterval: cfg.CleanupInterval,
+		staleWindow:     cfg.StaleWhileRevalidate,
+		negativeTTL:     cfg.NegativeTTL,
+		warmUpCount:     cfg.WarmUp.Count,
+		warmUpStrategy:  WarmUpStrategy(cfg.WarmUp.Strategy),
+
+		policy:     policy,
+		maxEntries: cfg.Eviction.MaxEntries,
+		maxBytes:   cfg.Eviction.MaxBytes,
+	}
+
+	if cfg.Snapshot.Enabled {
+		cache.snapshotPath = cfg.Snapshot.Path
+		cache.snapshotEvery = cfg.Snapshot.Interval
 	}
 
 	return cache
 }
 
+func (c *Cache) shardFor(orderUID string) *shard {
+	return c.shards[maphash.String(c.seed, orderUID)&c.mask]
+}
+
 func (c *Cache) Set(orderUID string, order *model.Order) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.setWithExpiry(orderUID, order, time.Now().Add(c.ttl))
+}
 
-	c.store[orderUID] = &cacheItem{
-		order:     order,
-		expiresAt: time.Now().Add(c.ttl),
-	}
+func (c *Cache) setWithExpiry(orderUID string, order *model.Order, expiresAt time.Time) {
+	s := c.shardFor(orderUID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(orderUID, order, expiresAt)
 }
 
 func (c *Cache) Get(orderUID string) *model.Order {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	s := c.shardFor(orderUID)
+	s.mu.RLock()
+	order := s.get(orderUID, time.Now())
+	s.mu.RUnlock()
 
-	item, exists := c.store[orderUID]
-	if !exists {
+	s.replayIfBacklogged()
+
+	return order
+}
+
+func (c *Cache) SetMissing(orderUID string) {
+	if c.negativeTTL <= 0 {
+		return
+	}
+
+	s := c.shardFor(orderUID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The order may have been stored while it was being looked up.
+	if _, exists := s.store[orderUID]; exists {
+		return
+	}
+	if _, exists := s.missing[orderUID]; !exists && len(s.missing) >= s.maxMissing {
+		return
+	}
+
+	s.missing[orderUID] = time.Now().Add(c.negativeTTL)
+}
+
+func (c *Cache) IsMissing(orderUID string) bool {
+	s := c.shardFor(orderUID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	expiresAt, exists := s.missing[orderUID]
+	if !exists || time.Now().After(expiresAt) {
+		return false
+	}
+
+	s.stats.missingHits.Add(1)
+
+	return true
+}
+
+func (c *Cache) GetStale(orderUID string) *model.Order {
+	if c.staleWindow <= 0 {
 		return nil
 	}
 
-	if time.Now().After(item.expiresAt) {
+	s := c.shardFor(orderUID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, exists := s.store[orderUID]
+	if !exists || time.Now().After(item.expiresAt.Add(c.staleWindow)) {
 		return nil
 	}
 
 	return item.order
 }
 
-func (c *Cache) cleanupExpired() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *Cache) Peek(orderUID string) *model.Order {
+	s := c.shardFor(orderUID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
+	item, exists := s.store[orderUID]
+	if !exists {
+		return nil
+	}
+
+	return item.order
+}
+
+// SetIndex caches orders under a secondary key, e.g. a track number.
+// The orders themselves are stored under their UIDs as with Set.
+func (c *Cache) SetIndex(key string, orders []*model.Order) {
+	expiresAt := time.Now().Add(c.ttl)
+	orderUIDs := make([]string, len(orders))
+	for i, order := range orders {
+		orderUIDs[i] = order.OrderUID
+		c.setWithExpiry(order.OrderUID, order, expiresAt)
+	}
+
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+
+	c.index[key] = &indexItem{
+		orderUIDs: orderUIDs,
+		expiresAt: expiresAt,
+	}
+}
+
+// GetIndex returns the orders cached under a secondary key, or nil if the key
+// or any of its orders is missing or expired.
+func (c *Cache) GetIndex(key string) []*model.Order {
 	now := time.Now()
-	for key, item := range c.store {
+
+	c.indexMu.RLock()
+	entry, exists := c.index[key]
+	c.indexMu.RUnlock()
+
+	if !exists || now.After(entry.expiresAt) {
+		return nil
+	}
+
+	orders := make([]*model.Order, 0, len(entry.orderUIDs))
+	for _, orderUID := range entry.orderUIDs {
+		order := c.indexedOrder(orderUID, now)
+		if order == nil {
+			return nil
+		}
+		orders = append(orders, order)
+	}
+
+	return orders
+}
+
+func (c *Cache) indexedOrder(orderUID string, now time.Time) *model.Order {
+	s := c.shardFor(orderUID)
+	s.mu.RLock()
+	item, exists := s.store[orderUID]
+	if !exists || now.After(item.expiresAt) {
+		s.mu.RUnlock()
+		return nil
+	}
+	s.recordAccess(orderUID)
+	order := item.order
+	s.mu.RUnlock()
+
+	s.replayIfBacklogged()
+
+	return order
+}
+
+func (c *Cache) DeleteIndex(keys ...string) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+
+	for _, key := range keys {
+		delete(c.index, key)
+	}
+}
+
+// sweepShard removes the expired entries of one shard in batches,
+// releasing the shard lock between them.
+func (c *Cache) sweepShard(s *shard) {
+	now := time.Now()
+	for more := true; more; {
+		more = s.sweepExpired(now, c.staleWindow, sweepBatch)
+	}
+	s.sweepMissing(now)
+}
+
+func (c *Cache) sweepIndex() {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+
+	now := time.Now()
+	for key, item := range c.index {
 		if now.After(item.expiresAt) {
-			delete(c.store, key)
+			delete(c.index, key)
 		}
 	}
 }
 
-func (c *Cache) GetAll() map[string]*model.Order {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache) Stats() model.CacheStats {
+	stats := model.CacheStats{
+		Policy:     string(c.policy),
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+	}
 
+	// Every entry lives for ttl, so the one expiring first was stored first.
+	now := time.Now()
+	var oldestAge time.Duration
+	for _, s := range c.shards {
+		s.mu.RLock()
+		stats.Entries += len(s.store)
+		stats.Bytes += s.bytes
+		stats.Hits += s.stats.hits.Load()
+		stats.Misses += s.stats.misses.Load()
+		stats.Evictions += s.stats.evictions
+		stats.EvictedBytes += s.stats.evictedBytes
+		stats.Expirations += s.stats.expirations
+		stats.Missing += len(s.missing)
+		stats.MissingHits += s.stats.missingHits.Load()
+		if front := s.expiry.Front(); front != nil {
+			oldestAge = max(oldestAge, now.Sub(front.Value.(*cacheItem).expiresAt.Add(-c.ttl)))
+		}
+		s.mu.RUnlock()
+	}
+
+	stats.HitRatio = model.HitRatio(stats.Hits, stats.Misses)
+	stats.OldestEntryAgeSeconds = oldestAge.Seconds()
+
+	return stats
+}
+
+// Keys returns the UIDs of all live entries in sorted order.
+func (c *Cache) Keys() []string {
+	now := time.Now()
+	var keys []string
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for key, item := range s.store {
+			if now.Before(item.expiresAt) {
+				keys = append(keys, key)
+			}
+		}
+		s.mu.RUnlock()
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Delete evicts a single order and reports whether it was cached.
+func (c *Cache) Delete(orderUID string) bool {
+	s := c.shardFor(orderUID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.missing, orderUID)
+
+	return s.delete(orderUID)
+}
+
+// Flush drops every entry, index and negative entry and returns how many orders were cached.
+func (c *Cache) Flush() int {
+	flushed := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		flushed += s.reset(c.policy)
+		s.mu.Unlock()
+	}
+
+	c.indexMu.Lock()
+	c.index = make(map[string]*indexItem)
+	c.indexMu.Unlock()
+
+	return flushed
+}
+
+// WarmUp loads the latest limit orders from the initializer, as the start-up warm-up does.
+func (c *Cache) WarmUp(ctx context.Context, limit int) (int, error) {
+	const op = "memory.Cache.WarmUp"
+
+	orders, err := c.initializer.GetOrdersForCache(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, order := range orders {
+		c.Set(order.OrderUID, order)
+	}
+
+	return len(orders), nil
+}
+
+func (c *Cache) GetAll() map[string]*model.Order {
 	result := make(map[string]*model.Order)
 	now := time.Now()
 
-	for key, item := range c.store {
-		if now.Before(item.expiresAt) {
-			result[key] = item.order
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for key, item := range s.store {
+			if now.Before(item.expiresAt) {
+				result[key] = item.order
+			}
 		}
+		s.mu.RUnlock()
 	}
 
 	return result
 }
 
+// flushReads hands the read counters collected since the last flush to the
+// initializer, which keeps them for the most_read warm-up strategy.
+func (c *Cache) flushReads(ctx context.Context) {
+	const op = "memory.Cache.flushReads"
+
+	reads := make(map[string]int64)
+	for _, s := range c.shards {
+		s.mu.Lock()
+		for key, count := range s.reads {
+			reads[key] += count
+		}
+		s.reads = make(map[string]int64)
+		s.mu.Unlock()
+
+		s.mu.RLock()
+		for key, item := range s.store {
+			if count := item.reads.Swap(0); count > 0 {
+				reads[key] += count
+			}
+		}
+		s.mu.RUnlock()
+	}
+
+	if err := c.initializer.RecordOrderReads(ctx, reads); err != nil {
+		c.log.Error("Failed to record order reads", "operation", op, "error", err.Error())
+	}
+}
+
+func (c *Cache) warmUpOrders(ctx context.Context) ([]*model.Order, error) {
+	if c.warmUpCount == 0 {
+		return nil, nil
+	}
+
+	if c.warmUpStrategy == WarmUpMostRead {
+		return c.initializer.GetMostReadOrdersForCache(ctx, c.warmUpCount)
+	}
+
+	return c.initializer.GetOrdersForCache(ctx, c.warmUpCount)
+}
+
 func (c *Cache) Run(ctx context.Context) error {
 	const op = "memory.Cache.Run"
 
-	orders, err := c.initializer.GetOrdersForCache(ctx, limit)
+	var snapshotTick <-chan time.Time
+	if c.snapshotPath != "" {
+		// Only the first start loads the snapshot. On a restart the entries in memory
+		// are newer than a snapshot taken up to an interval ago.
+		c.snapshotOnce.Do(func() {
+			if err := c.loadSnapshot(); err != nil {
+				c.log.Error("Skipping cache snapshot", "operation", op, "error", err.Error())
+			}
+		})
+
+		snapshotTicker := time.NewTicker(c.snapshotEvery)
+		defer snapshotTicker.Stop()
+		snapshotTick = snapshotTicker.C
+	}
+
+	orders, err := c.warmUpOrders(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	c.log.Info("Successfully got orders for cache",
 		"operation", op,
-		"limit", limit,
+		"strategy", c.warmUpStrategy,
+		"limit", c.warmUpCount,
 		"orders_count", len(orders),
 	)
 
@@ -128,14 +518,42 @@ func (c *Cache) Run(ctx context.Context) error {
 	} else {
 		c.log.Warn("No orders found for cache initialization")
 	}
+	c.warmedUp.Store(true)
 
-	cleanupTicker := time.NewTicker(c.ttl / 2)
-	defer cleanupTicker.Stop()
+	// Shards are swept one per tick, so each is swept once per cleanup interval
+	// and the work is spread over it.
+	sweepTicker := time.NewTicker(max(time.Millisecond, c.cleanupInterval/time.Duration(len(c.shards))))
+	defer sweepTicker.Stop()
 
+	next := 0
 	for {
 		select {
-		case <-cleanupTicker.C:
-			c.cleanupExpired()
+		case <-sweepTicker.C:
+			c.sweepShard(c.shards[next])
+			next = (next + 1) % len(c.shards)
+			if next != 0 {
+				continue
+			}
+
+			c.sweepIndex()
+			c.flushReads(ctx)
+
+			stats := c.Stats()
+			c.log.Info("Cache stats",
+				"operation", op,
+				"policy", stats.Policy,
+				"shards", len(c.shards),
+				"entries", stats.Entries,
+				"bytes", stats.Bytes,
+				"hits", stats.Hits,
+				"misses", stats.Misses,
+				"evictions", stats.Evictions,
+				"expirations", stats.Expirations,
+			)
+		case <-snapshotTick:
+			if err = c.saveSnapshot(); err != nil {
+				c.log.Error("Failed to save cache snapshot", "operation", op, "error", err.Error())
+			}
 		case <-c.stopChan:
 			return nil
 		case <-ctx.Done():
@@ -143,7 +561,22 @@ func (c *Cache) Run(ctx context.Context) error {
 		}
 	}
 }
-func (c *Cache) Shutdown(_ context.Context) error {
-	close(c.stopChan)
+
+// HealthCheck reports the cache ready once the start-up warm-up has finished.
+func (c *Cache) HealthCheck(_ context.Context) appPorts.Health {
+	if !c.warmedUp.Load() {
+		return appPorts.NotReady("warm-up in progress")
+	}
+	return appPorts.Up()
+}
+
+func (c *Cache) Shutdown(ctx context.Context) error {
+	c.flushReads(ctx)
+	c.stopOnce.Do(func() { close(c.stopChan) })
+
+	if c.snapshotPath != "" {
+		return c.saveSnapshot()
+	}
+
 	return nil
 }
@@ -0,0 +1,215 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/service/mapper"
+)
+
+const benchKeys = 50000
+
+// noopInitializer stands in for storage: there is nothing to warm up from
+// and read counters are dropped.
+type noopInitializer struct{}
+
+func (noopInitializer) GetOrdersForCache(context.Context, int) ([]*model.Order, error) {
+	return nil, nil
+}
+
+func (noopInitializer) GetMostReadOrdersForCache(context.Context, int) ([]*model.Order, error) {
+	return nil, nil
+}
+
+func (noopInitializer) RecordOrderReads(context.Context, map[string]int64) error {
+	return nil
+}
+
+func newTestCache(cfg *config.Cache) *Cache {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewCache(log, cfg, noopInitializer{})
+}
+
+func generateOrders(count int) []*model.Order {
+	generator := mock.NewMockGenerator()
+	orders := make([]*model.Order, count)
+	for i := range orders {
+		orders[i] = mapper.OrderFromDTO(generator.GenerateOrder())
+	}
+	return orders
+}
+
+func TestPerShardAddsUpToBound(t *testing.T) {
+	tests := []struct {
+		maxEntries int
+		shards     int
+		wantShards int
+	}{
+		{maxEntries: 100, shards: 16, wantShards: 16},
+		{maxEntries: 16, shards: 16, wantShards: 16},
+		{maxEntries: 7, shards: 16, wantShards: 4},
+		{maxEntries: 1, shards: 64, wantShards: 1},
+		{maxEntries: 50000, shards: 1024, wantShards: 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d over %d", tt.maxEntries, tt.shards), func(t *testing.T) {
+			cache := newTestCache(&config.Cache{
+				TTL:      time.Minute,
+				Shards:   tt.shards,
+				Eviction: config.CacheEviction{Policy: string(EvictionLRU), MaxEntries: tt.maxEntries},
+			})
+
+			if len(cache.shards) != tt.wantShards {
+				t.Fatalf("shards = %d, want %d", len(cache.shards), tt.wantShards)
+			}
+
+			total := 0
+			for _, s := range cache.shards {
+				if s.maxEntries < 1 {
+					t.Fatalf("shard bound = %d, want at least 1", s.maxEntries)
+				}
+				total += s.maxEntries
+			}
+			if total != tt.maxEntries {
+				t.Fatalf("shard bounds add up to %d, want %d", total, tt.maxEntries)
+			}
+		})
+	}
+}
+
+func TestSetIsAlwaysAdmitted(t *testing.T) {
+	for _, policy := range []EvictionPolicy{EvictionLRU, EvictionLFU, EvictionTinyLFU} {
+		t.Run(string(policy), func(t *testing.T) {
+			cache := newTestCache(&config.Cache{
+				TTL:      time.Minute,
+				Shards:   1,
+				Eviction: config.CacheEviction{Policy: string(policy), MaxEntries: 10},
+			})
+
+			// Popular entries fill the cache first, so new ones lose any frequency contest.
+			for i := range 10 {
+				key := fmt.Sprintf("hot-%d", i)
+				cache.Set(key, &model.Order{OrderUID: key})
+				for range 10 {
+					cache.Get(key)
+				}
+			}
+
+			for i := range 100 {
+				key := fmt.Sprintf("new-%d", i)
+				cache.Set(key, &model.Order{OrderUID: key})
+				if cache.Get(key) == nil {
+					t.Fatalf("%s was not admitted", key)
+				}
+			}
+
+			if entries := cache.Stats().Entries; entries != 10 {
+				t.Fatalf("entries = %d, want 10", entries)
+			}
+		})
+	}
+}
+
+// BenchmarkCacheGet measures hits on a full cache for every eviction policy.
+func BenchmarkCacheGet(b *testing.B) {
+	orders := generateOrders(benchKeys)
+
+	for _, policy := range []EvictionPolicy{EvictionLRU, EvictionLFU, EvictionTinyLFU} {
+		b.Run(string(policy), func(b *testing.B) {
+			cache := newTestCache(&config.Cache{
+				TTL:      time.Hour,
+				Shards:   defaultShards,
+				Eviction: config.CacheEviction{Policy: string(policy), MaxEntries: benchKeys},
+			})
+			for _, order := range orders {
+				cache.Set(order.OrderUID, order)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					cache.Get(orders[rand.IntN(len(orders))].OrderUID)
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkCacheMixed compares shard counts under a read-mostly load. Entries expire
+// during the run and the cache runs its sweeps, so they compete with the readers as
+// they do in production.
+func BenchmarkCacheMixed(b *testing.B) {
+	const readRatio = 0.9
+
+	orders := generateOrders(benchKeys)
+
+	for _, shards := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			cache := newTestCache(&config.Cache{
+				TTL:             time.Second,
+				CleanupInterval: 500 * time.Millisecond,
+				Shards:          shards,
+				Eviction: config.CacheEviction{
+					Policy:     string(EvictionTinyLFU),
+					MaxEntries: benchKeys,
+				},
+			})
+			for _, order := range orders {
+				cache.Set(order.OrderUID, order)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			runDone := make(chan struct{})
+			go func() {
+				defer close(runDone)
+				_ = cache.Run(ctx)
+			}()
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					order := orders[rand.IntN(len(orders))]
+					if rand.Float64() >= readRatio {
+						cache.Set(order.OrderUID, order)
+						continue
+					}
+					if cache.Get(order.OrderUID) == nil {
+						cache.Set(order.OrderUID, order)
+					}
+				}
+			})
+			b.StopTimer()
+
+			cancel()
+			<-runDone
+
+			stats := cache.Stats()
+			b.ReportMetric(stats.HitRatio, "hit-ratio")
+			b.ReportMetric(float64(stats.Expirations), "expirations")
+		})
+	}
+}
+
+func TestShutdownTwice(t *testing.T) {
+	cache := newTestCache(&config.Cache{
+		TTL:      time.Minute,
+		Shards:   1,
+		Eviction: config.CacheEviction{Policy: string(EvictionLRU), MaxEntries: 10},
+	})
+
+	if err := cache.Shutdown(context.Background()); err != nil {
+		t.Fatalf("first Shutdown: %v", err)
+	}
+	if err := cache.Shutdown(context.Background()); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+}
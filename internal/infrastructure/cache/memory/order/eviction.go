@@ -0,0 +1,217 @@
+package order
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+)
+
+type EvictionPolicy string
+
+const (
+	EvictionLRU     EvictionPolicy = "lru"
+	EvictionLFU     EvictionPolicy = "lfu"
+	EvictionTinyLFU EvictionPolicy = "w-tinylfu"
+)
+
+func ParseEvictionPolicy(value string) (EvictionPolicy, error) {
+	switch policy := EvictionPolicy(value); policy {
+	case EvictionLRU, EvictionLFU, EvictionTinyLFU:
+		return policy, nil
+	case "":
+		return EvictionTinyLFU, nil
+	default:
+		return "", fmt.Errorf("unknown eviction policy %q", value)
+	}
+}
+
+// evictor tracks the keys held by the cache and picks which one to drop
+// when the cache goes over its bounds. It is not safe for concurrent use.
+type evictor interface {
+	// add registers a new key; full reports that the cache is over its bounds
+	// and victim is about to be called.
+	add(key string, full bool)
+	access(key string)
+	remove(key string)
+	// victim removes and returns the key to evict. It passes over keep, the key
+	// just written, so an explicit write is always admitted; it reports false
+	// when keep is the only key left.
+	victim(keep string) (string, bool)
+}
+
+func newEvictor(policy EvictionPolicy, capacity int) evictor {
+	switch policy {
+	case EvictionLRU:
+		return newLRU()
+	case EvictionLFU:
+		return newLFU()
+	default:
+		return newTinyLFU(capacity)
+	}
+}
+
+type lru struct {
+	order *list.List
+	items map[string]*list.Element
+}
+
+func newLRU() *lru {
+	return &lru{
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (l *lru) add(key string, _ bool) {
+	if elem, ok := l.items[key]; ok {
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.items[key] = l.order.PushFront(key)
+}
+
+func (l *lru) access(key string) {
+	if elem, ok := l.items[key]; ok {
+		l.order.MoveToFront(elem)
+	}
+}
+
+func (l *lru) remove(key string) {
+	if elem, ok := l.items[key]; ok {
+		l.order.Remove(elem)
+		delete(l.items, key)
+	}
+}
+
+func (l *lru) victim(keep string) (string, bool) {
+	key, ok := l.oldestExcept(keep)
+	if ok {
+		l.remove(key)
+	}
+	return key, ok
+}
+
+func (l *lru) len() int {
+	return l.order.Len()
+}
+
+// oldestExcept returns the least recently used key other than keep.
+func (l *lru) oldestExcept(keep string) (string, bool) {
+	elem := l.order.Back()
+	if elem != nil && elem.Value.(string) == keep {
+		elem = elem.Prev()
+	}
+	if elem == nil {
+		return "", false
+	}
+	return elem.Value.(string), true
+}
+
+func (l *lru) contains(key string) bool {
+	_, ok := l.items[key]
+	return ok
+}
+
+// lfu evicts the least frequently used key, the least recently used one among equals.
+type lfu struct {
+	entries lfuHeap
+	items   map[string]*lfuEntry
+	tick    uint64
+}
+
+type lfuEntry struct {
+	key   string
+	freq  uint64
+	tick  uint64
+	index int
+}
+
+func newLFU() *lfu {
+	return &lfu{
+		items: make(map[string]*lfuEntry),
+	}
+}
+
+func (l *lfu) add(key string, _ bool) {
+	if _, ok := l.items[key]; ok {
+		l.access(key)
+		return
+	}
+	l.tick++
+	entry := &lfuEntry{key: key, freq: 1, tick: l.tick}
+	l.items[key] = entry
+	heap.Push(&l.entries, entry)
+}
+
+func (l *lfu) access(key string) {
+	entry, ok := l.items[key]
+	if !ok {
+		return
+	}
+	l.tick++
+	entry.freq++
+	entry.tick = l.tick
+	heap.Fix(&l.entries, entry.index)
+}
+
+func (l *lfu) remove(key string) {
+	entry, ok := l.items[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&l.entries, entry.index)
+	delete(l.items, key)
+}
+
+func (l *lfu) victim(keep string) (string, bool) {
+	if l.entries.Len() == 0 {
+		return "", false
+	}
+
+	// A key just written has the lowest frequency, so it is usually on top.
+	entry := heap.Pop(&l.entries).(*lfuEntry)
+	if entry.key == keep {
+		if l.entries.Len() == 0 {
+			heap.Push(&l.entries, entry)
+			return "", false
+		}
+		kept := entry
+		entry = heap.Pop(&l.entries).(*lfuEntry)
+		heap.Push(&l.entries, kept)
+	}
+
+	delete(l.items, entry.key)
+	return entry.key, true
+}
+
+type lfuHeap []*lfuEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].tick < h[j].tick
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	entry := x.(*lfuEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
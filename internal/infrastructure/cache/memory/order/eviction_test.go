@@ -0,0 +1,120 @@
+package order
+
+import (
+	"fmt"
+	"testing"
+)
+
+// evictAll drains the evictor and returns its keys in eviction order.
+func evictAll(t *testing.T, e evictor) []string {
+	t.Helper()
+
+	var victims []string
+	for {
+		key, ok := e.victim("")
+		if !ok {
+			return victims
+		}
+		victims = append(victims, key)
+	}
+}
+
+func assertOrder(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("eviction order = %v, want %v", got, want)
+	}
+}
+
+func TestLRUVictimOrder(t *testing.T) {
+	l := newLRU()
+	for _, key := range []string{"a", "b", "c"} {
+		l.add(key, false)
+	}
+	l.access("a")
+
+	assertOrder(t, evictAll(t, l), "b", "c", "a")
+}
+
+func TestLRUVictimPassesOverKeep(t *testing.T) {
+	l := newLRU()
+	l.add("a", false)
+	l.add("b", false)
+
+	if key, _ := l.victim("a"); key != "b" {
+		t.Fatalf("victim = %q, want b", key)
+	}
+	if _, ok := l.victim("a"); ok {
+		t.Fatal("victim evicted the only key left, which is kept")
+	}
+}
+
+func TestLFUVictimOrder(t *testing.T) {
+	l := newLFU()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		l.add(key, false)
+	}
+	l.access("a")
+	l.access("a")
+	l.access("c")
+	// Re-adding counts as a request, like access.
+	l.add("d", false)
+
+	// b is used least; c and d are used as often, c less recently.
+	assertOrder(t, evictAll(t, l), "b", "c", "d", "a")
+}
+
+// newFilledTinyLFU adds keys k0..k(n-1). The window holds one key, so every key
+// but the last moves on to probation.
+func newFilledTinyLFU(n int) *tinyLFU {
+	t := newTinyLFU(100)
+	for i := range n {
+		t.add(fmt.Sprintf("k%d", i), false)
+	}
+	return t
+}
+
+func TestTinyLFURejectsUnpopularCandidate(t *testing.T) {
+	lfu := newFilledTinyLFU(5)
+
+	// k4 leaves the window as often as k0, the oldest key on probation: it is not admitted.
+	lfu.add("new", true)
+	if key, _ := lfu.victim("new"); key != "k4" {
+		t.Fatalf("victim = %q, want the window candidate k4", key)
+	}
+}
+
+func TestTinyLFUAdmitsPopularCandidate(t *testing.T) {
+	lfu := newFilledTinyLFU(5)
+	lfu.access("k4")
+	lfu.access("k4")
+
+	// k4 is requested more often than k0, so k0 makes room for it.
+	lfu.add("new", true)
+	if key, _ := lfu.victim("new"); key != "k0" {
+		t.Fatalf("victim = %q, want the probation victim k0", key)
+	}
+	if !lfu.probation.contains("k4") {
+		t.Fatal("admitted candidate k4 is not on probation")
+	}
+}
+
+func TestTinyLFUProtectsFrequentKeys(t *testing.T) {
+	lfu := newFilledTinyLFU(5)
+	lfu.access("k0")
+
+	// k0 was promoted to protected, so probation is drained first, oldest first.
+	// The window is within its share, so its key k4 goes last.
+	assertOrder(t, evictAll(t, lfu), "k1", "k2", "k3", "k0", "k4")
+}
+
+func TestTinyLFUReAddCountsOnce(t *testing.T) {
+	lfu := newTinyLFU(100)
+	lfu.add("a", false)
+	lfu.add("a", false)
+
+	if got := lfu.sketch.estimate("a"); got != 2 {
+		t.Fatalf("estimate after two writes = %d, want 2", got)
+	}
+}
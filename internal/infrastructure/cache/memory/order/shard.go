@@ -0,0 +1,280 @@
+package order
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+const (
+	defaultShards = 16
+	maxShards     = 1024
+	// sweepBatch bounds how many expired entries are removed under one lock hold,
+	// so a sweep never blocks readers of a shard for long.
+	sweepBatch = 256
+	// accessBuffer is how many reads a shard remembers for its evictor between writes.
+	accessBuffer = 128
+)
+
+// shard is an independently locked segment of the cache with its own bounds and
+// evictor. Methods expect the caller to hold mu, except the sweeps, which take it
+// themselves. get only needs the read lock: the evictor is not safe for concurrent
+// use, so reads are queued in accessed and replayed to it under the write lock.
+type shard struct {
+	mu      sync.RWMutex
+	store   map[string]*cacheItem
+	missing map[string]time.Time
+	// reads keeps the read counters of entries removed since the last flush.
+	reads    map[string]int64
+	accessed chan string
+	// expiry holds the items of store ordered by expiresAt, the first to expire in front.
+	expiry *list.List
+
+	evictor    evictor
+	maxEntries int
+	maxBytes   int64
+	maxMissing int
+	bytes      int64
+	stats      cacheCounters
+}
+
+func newShard(policy EvictionPolicy, maxEntries int, maxBytes int64, maxMissing int) *shard {
+	return &shard{
+		store:      make(map[string]*cacheItem),
+		missing:    make(map[string]time.Time),
+		reads:      make(map[string]int64),
+		accessed:   make(chan string, accessBuffer),
+		expiry:     list.New(),
+		evictor:    newEvictor(policy, maxEntries),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		maxMissing: maxMissing,
+	}
+}
+
+// shardCount rounds n up to a power of two so a shard is picked with a mask.
+func shardCount(n int) int {
+	if n < 1 {
+		n = defaultShards
+	}
+	n = min(n, maxShards)
+
+	count := 1
+	for count < n {
+		count <<= 1
+	}
+	return count
+}
+
+// shardsFor halves count until every shard can hold at least one of maxEntries.
+func shardsFor(count, maxEntries int) int {
+	for maxEntries > 0 && count > maxEntries {
+		count >>= 1
+	}
+	return count
+}
+
+// perShard returns the share of a cache-wide bound held by shard i, spreading the
+// remainder over the first shards so the shares add up to the bound exactly.
+// Zero stays unbounded, and a share is never zero, which would lift the bound.
+func perShard[T int | int64](bound T, shards, i int) T {
+	if bound <= 0 {
+		return bound
+	}
+
+	share := bound / T(shards)
+	if T(i) < bound%T(shards) {
+		share++
+	}
+	return max(1, share)
+}
+
+// set stores the order and evicts entries until the shard is back within its bounds.
+// The order itself is never evicted here, so a single entry larger than the byte
+// bound stays until the next write.
+func (s *shard) set(orderUID string, order *model.Order, expiresAt time.Time) {
+	delete(s.missing, orderUID)
+	s.replayAccesses()
+
+	size := orderSize(order)
+
+	if item, exists := s.store[orderUID]; exists {
+		s.bytes += size - item.size
+		item.order = order
+		item.size = size
+		item.expiresAt = expiresAt
+		s.placeExpiry(item)
+		s.evictor.access(orderUID)
+	} else {
+		item = &cacheItem{
+			key:       orderUID,
+			order:     order,
+			size:      size,
+			expiresAt: expiresAt,
+		}
+		s.store[orderUID] = item
+		s.placeExpiry(item)
+		s.bytes += size
+		s.evictor.add(orderUID, s.overCapacity())
+	}
+
+	for s.overCapacity() {
+		key, ok := s.evictor.victim(orderUID)
+		if !ok {
+			return
+		}
+		if item, exists := s.store[key]; exists {
+			s.stats.evictions++
+			s.stats.evictedBytes += item.size
+			s.removeItem(item)
+		}
+	}
+}
+
+// placeExpiry moves the item to its place in the expiry list. Entries are nearly always
+// stored with the latest expiry, so the walk from the back usually stops right away.
+func (s *shard) placeExpiry(item *cacheItem) {
+	if item.elem == nil {
+		item.elem = s.expiry.PushBack(item)
+	} else {
+		s.expiry.MoveToBack(item.elem)
+	}
+
+	for prev := item.elem.Prev(); prev != nil; prev = item.elem.Prev() {
+		if !prev.Value.(*cacheItem).expiresAt.After(item.expiresAt) {
+			return
+		}
+		s.expiry.MoveBefore(item.elem, prev)
+	}
+}
+
+func (s *shard) overCapacity() bool {
+	return (s.maxEntries > 0 && len(s.store) > s.maxEntries) ||
+		(s.maxBytes > 0 && s.bytes > s.maxBytes)
+}
+
+// removeItem drops an item the evictor no longer tracks.
+func (s *shard) removeItem(item *cacheItem) {
+	if reads := item.reads.Load(); reads > 0 {
+		s.reads[item.key] += reads
+	}
+	s.bytes -= item.size
+	s.expiry.Remove(item.elem)
+	delete(s.store, item.key)
+}
+
+func (s *shard) delete(orderUID string) bool {
+	item, exists := s.store[orderUID]
+	if !exists {
+		return false
+	}
+
+	s.evictor.remove(orderUID)
+	s.removeItem(item)
+
+	return true
+}
+
+// get looks the order up under the read lock.
+func (s *shard) get(orderUID string, now time.Time) *model.Order {
+	item, exists := s.store[orderUID]
+	if !exists || now.After(item.expiresAt) {
+		s.stats.misses.Add(1)
+		return nil
+	}
+
+	s.stats.hits.Add(1)
+	item.reads.Add(1)
+	s.recordAccess(orderUID)
+
+	return item.order
+}
+
+// recordAccess queues a read for the evictor. It is safe under the read lock; when
+// the queue is full the read is dropped, which only costs eviction precision.
+func (s *shard) recordAccess(orderUID string) {
+	select {
+	case s.accessed <- orderUID:
+	default:
+	}
+}
+
+// replayAccesses hands the queued reads to the evictor. The caller holds the write lock.
+func (s *shard) replayAccesses() {
+	for {
+		select {
+		case key := <-s.accessed:
+			s.evictor.access(key)
+		default:
+			return
+		}
+	}
+}
+
+// replayIfBacklogged replays queued reads once the queue is half full, so a shard
+// that is read much more than it is written still keeps its recency order. It skips
+// the replay rather than wait for the write lock.
+func (s *shard) replayIfBacklogged() {
+	if len(s.accessed) < accessBuffer/2 || !s.mu.TryLock() {
+		return
+	}
+	defer s.mu.Unlock()
+
+	s.replayAccesses()
+}
+
+// sweepExpired removes at most limit entries that are past their stale window
+// and reports whether more of them may be left.
+func (s *shard) sweepExpired(now time.Time, staleWindow time.Duration, limit int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for range limit {
+		front := s.expiry.Front()
+		if front == nil {
+			return false
+		}
+
+		item := front.Value.(*cacheItem)
+		if !now.After(item.expiresAt.Add(staleWindow)) {
+			return false
+		}
+
+		s.stats.expirations++
+		s.delete(item.key)
+	}
+
+	return true
+}
+
+func (s *shard) sweepMissing(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, expiresAt := range s.missing {
+		if now.After(expiresAt) {
+			delete(s.missing, key)
+		}
+	}
+}
+
+func (s *shard) reset(policy EvictionPolicy) int {
+	flushed := len(s.store)
+
+	for key, item := range s.store {
+		if reads := item.reads.Load(); reads > 0 {
+			s.reads[key] += reads
+		}
+	}
+	s.replayAccesses()
+
+	s.store = make(map[string]*cacheItem)
+	s.missing = make(map[string]time.Time)
+	s.expiry.Init()
+	s.evictor = newEvictor(policy, s.maxEntries)
+	s.bytes = 0
+
+	return flushed
+}
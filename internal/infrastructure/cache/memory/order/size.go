@@ -0,0 +1,35 @@
+package order
+
+import (
+	"unsafe"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+// entryOverhead approximates the map bucket, cache item and eviction
+// bookkeeping held for every cached order.
+const entryOverhead = 128
+
+// orderSize approximates the memory held by an order: its structs plus string contents.
+// It is used for the byte bound only and does not need to be exact.
+func orderSize(order *model.Order) int64 {
+	size := int64(unsafe.Sizeof(*order)) + entryOverhead + 2*int64(len(order.OrderUID))
+	size += int64(len(order.TrackNumber) + len(order.Entry) + len(order.Locale) +
+		len(order.InternalSignature) + len(order.CustomerID) + len(order.DeliveryService) +
+		len(order.ShardKey) + len(order.OofShard))
+
+	delivery := order.Delivery
+	size += int64(len(delivery.Name) + len(delivery.Phone) + len(delivery.Zip) + len(delivery.City) +
+		len(delivery.Address) + len(delivery.Region) + len(delivery.Email))
+
+	payment := order.Payment
+	size += int64(len(payment.Transaction) + len(payment.RequestID) + len(payment.Currency) +
+		len(payment.Provider) + len(payment.Bank))
+
+	for _, item := range order.Items {
+		size += int64(unsafe.Sizeof(item))
+		size += int64(len(item.TrackNumber) + len(item.RID) + len(item.Name) + len(item.Size) + len(item.Brand))
+	}
+
+	return size
+}
@@ -0,0 +1,185 @@
+package order
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/codec"
+)
+
+// A snapshot file is laid out as
+//
+//	magic | version | entry count | entries... | CRC-32C of everything before it
+//
+// where every entry is its expiry in Unix milliseconds followed by the encoded order.
+const (
+	snapshotMagic   = "OCSN"
+	snapshotVersion = 1
+)
+
+var (
+	errSnapshotCorrupt = errors.New("snapshot is corrupt")
+	errSnapshotVersion = errors.New("unsupported snapshot version")
+)
+
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+type snapshotEntry struct {
+	order     *model.Order
+	expiresAt time.Time
+}
+
+// liveEntries returns the entries GetAll would return, with their expiry.
+func (c *Cache) liveEntries() []snapshotEntry {
+	now := time.Now()
+	var entries []snapshotEntry
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for _, item := range s.store {
+			if now.Before(item.expiresAt) {
+				entries = append(entries, snapshotEntry{order: item.order, expiresAt: item.expiresAt})
+			}
+		}
+		s.mu.RUnlock()
+	}
+
+	return entries
+}
+
+// saveSnapshot writes the live entries to a temporary file next to the snapshot path
+// and renames it into place, so a crash mid-write never leaves a partial snapshot.
+func (c *Cache) saveSnapshot() error {
+	const op = "memory.Cache.saveSnapshot"
+
+	entries := c.liveEntries()
+
+	buf := make([]byte, 0, 64+len(entries)*512)
+	buf = append(buf, snapshotMagic...)
+	buf = append(buf, snapshotVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(entries)))
+	for _, entry := range entries {
+		buf = binary.AppendVarint(buf, entry.expiresAt.UnixMilli())
+		buf = codec.AppendOrder(buf, entry.order)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, snapshotTable))
+
+	dir := filepath.Dir(c.snapshotPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.snapshotPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = os.Rename(tmp.Name(), c.snapshotPath); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("Cache snapshot saved", "operation", op, "path", c.snapshotPath, "entries", len(entries))
+
+	return nil
+}
+
+// loadSnapshot restores entries that have not expired yet. A missing file is not an
+// error; a corrupt or incompatible one is reported and nothing is loaded from it.
+func (c *Cache) loadSnapshot() error {
+	const op = "memory.Cache.loadSnapshot"
+
+	data, err := os.ReadFile(c.snapshotPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries, err := decodeSnapshot(data)
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", op, c.snapshotPath, err)
+	}
+
+	// Loading in expiry order keeps every insert at the back of its shard's expiry list.
+	slices.SortFunc(entries, func(a, b snapshotEntry) int {
+		return a.expiresAt.Compare(b.expiresAt)
+	})
+
+	now := time.Now()
+	loaded := 0
+	for _, entry := range entries {
+		if now.Before(entry.expiresAt) {
+			c.setWithExpiry(entry.order.OrderUID, entry.order, entry.expiresAt)
+			loaded++
+		}
+	}
+
+	c.log.Info("Cache snapshot loaded",
+		"operation", op,
+		"path", c.snapshotPath,
+		"entries", len(entries),
+		"loaded", loaded,
+	)
+
+	return nil
+}
+
+func decodeSnapshot(data []byte) ([]snapshotEntry, error) {
+	header := len(snapshotMagic) + 1
+	if len(data) < header+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, errSnapshotCorrupt
+	}
+	if data[len(snapshotMagic)] != snapshotVersion {
+		return nil, fmt.Errorf("%w: %d", errSnapshotVersion, data[len(snapshotMagic)])
+	}
+
+	body, sum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.Checksum(body, snapshotTable) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", errSnapshotCorrupt)
+	}
+
+	rest := body[header:]
+	count, n := binary.Uvarint(rest)
+	if n <= 0 || count > uint64(len(rest)) {
+		return nil, errSnapshotCorrupt
+	}
+	rest = rest[n:]
+
+	entries := make([]snapshotEntry, 0, count)
+	for range count {
+		expiresAt, n := binary.Varint(rest)
+		if n <= 0 {
+			return nil, errSnapshotCorrupt
+		}
+		rest = rest[n:]
+
+		order, n, err := codec.DecodeOrder(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errSnapshotCorrupt, err)
+		}
+		rest = rest[n:]
+
+		entries = append(entries, snapshotEntry{order: order, expiresAt: time.UnixMilli(expiresAt)})
+	}
+
+	return entries, nil
+}
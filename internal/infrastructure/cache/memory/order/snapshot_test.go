@@ -0,0 +1,218 @@
+package order
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+)
+
+func newSnapshotCache(t *testing.T) *Cache {
+	t.Helper()
+
+	return newTestCache(&config.Cache{
+		TTL:    time.Hour,
+		Shards: 4,
+		Snapshot: config.CacheSnapshot{
+			Enabled:  true,
+			Path:     filepath.Join(t.TempDir(), "cache.snapshot"),
+			Interval: time.Minute,
+		},
+	})
+}
+
+// validSnapshot saves a snapshot of a few orders and returns its bytes.
+func validSnapshot(t *testing.T) []byte {
+	t.Helper()
+
+	cache := newSnapshotCache(t)
+	for _, order := range generateOrders(3) {
+		cache.Set(order.OrderUID, order)
+	}
+	if err := cache.saveSnapshot(); err != nil {
+		t.Fatalf("saveSnapshot: %v", err)
+	}
+
+	data, err := os.ReadFile(cache.snapshotPath)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	return data
+}
+
+// resum replaces the checksum, so the damage is left for the decoder itself to find.
+func resum(data []byte) []byte {
+	body := data[:len(data)-4]
+	return binary.BigEndian.AppendUint32(body, crc32.Checksum(body, snapshotTable))
+}
+
+func TestDecodeSnapshot(t *testing.T) {
+	valid := validSnapshot(t)
+	header := len(snapshotMagic) + 1
+
+	tests := []struct {
+		name    string
+		data    func() []byte
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			data:    func() []byte { return valid },
+			wantErr: nil,
+		},
+		{
+			name: "bad magic",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				copy(data, "XXXX")
+				return resum(data)
+			},
+			wantErr: errSnapshotCorrupt,
+		},
+		{
+			name: "version mismatch",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				data[len(snapshotMagic)] = snapshotVersion + 1
+				return resum(data)
+			},
+			wantErr: errSnapshotVersion,
+		},
+		{
+			name: "crc mismatch",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				data[header+2] ^= 0xff
+				return data
+			},
+			wantErr: errSnapshotCorrupt,
+		},
+		{
+			name:    "empty file",
+			data:    func() []byte { return nil },
+			wantErr: errSnapshotCorrupt,
+		},
+		{
+			name:    "truncated header",
+			data:    func() []byte { return valid[:header] },
+			wantErr: errSnapshotCorrupt,
+		},
+		{
+			name:    "truncated body",
+			data:    func() []byte { return valid[:len(valid)/2] },
+			wantErr: errSnapshotCorrupt,
+		},
+		{
+			name: "truncated body with valid crc",
+			data: func() []byte {
+				return resum(append([]byte(nil), valid[:len(valid)/2+4]...))
+			},
+			wantErr: errSnapshotCorrupt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := decodeSnapshot(tt.data())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(entries) != 3 {
+				t.Fatalf("entries = %d, want 3", len(entries))
+			}
+		})
+	}
+}
+
+func TestLoadSnapshotSkipsCorruptFile(t *testing.T) {
+	data := validSnapshot(t)
+	data[len(data)-1] ^= 0xff
+
+	cache := newSnapshotCache(t)
+	if err := os.WriteFile(cache.snapshotPath, data, 0o600); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	if err := cache.loadSnapshot(); !errors.Is(err, errSnapshotCorrupt) {
+		t.Fatalf("err = %v, want %v", err, errSnapshotCorrupt)
+	}
+	if entries := cache.Stats().Entries; entries != 0 {
+		t.Fatalf("entries = %d, want none loaded", entries)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	cache := newSnapshotCache(t)
+	orders := generateOrders(10)
+	for _, order := range orders {
+		cache.Set(order.OrderUID, order)
+	}
+	if err := cache.saveSnapshot(); err != nil {
+		t.Fatalf("saveSnapshot: %v", err)
+	}
+
+	restored := newTestCache(&config.Cache{TTL: time.Hour, Shards: 4})
+	restored.snapshotPath = cache.snapshotPath
+	if err := restored.loadSnapshot(); err != nil {
+		t.Fatalf("loadSnapshot: %v", err)
+	}
+
+	for _, order := range orders {
+		got := restored.Get(order.OrderUID)
+		if got == nil || got.OrderUID != order.OrderUID || len(got.Items) != len(order.Items) {
+			t.Fatalf("order %s not restored", order.OrderUID)
+		}
+	}
+}
+
+// runOnce runs the cache until its warm-up has finished, then stops it.
+func runOnce(t *testing.T, cache *Cache) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cache.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cache.warmedUp.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("cache did not warm up")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestSnapshotLoadedOnFirstRunOnly(t *testing.T) {
+	cache := newSnapshotCache(t)
+	order := generateOrders(1)[0]
+	cache.Set(order.OrderUID, order)
+	if err := cache.saveSnapshot(); err != nil {
+		t.Fatalf("saveSnapshot: %v", err)
+	}
+	cache.Delete(order.OrderUID)
+
+	runOnce(t, cache)
+	if cache.Get(order.OrderUID) == nil {
+		t.Fatal("snapshot was not loaded on the first run")
+	}
+
+	updated := *order
+	updated.TrackNumber = "UPDATED"
+	cache.Set(order.OrderUID, &updated)
+
+	runOnce(t, cache)
+	if got := cache.Get(order.OrderUID); got == nil || got.TrackNumber != "UPDATED" {
+		t.Fatal("a restart overwrote a newer entry with the snapshot")
+	}
+}
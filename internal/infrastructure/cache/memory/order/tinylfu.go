@@ -0,0 +1,192 @@
+package order
+
+import (
+	"hash/maphash"
+)
+
+const (
+	tinyLFUWindowPercent    = 1
+	tinyLFUProtectedPercent = 80
+	sketchDepth             = 4
+	sketchMinWidth          = 64
+)
+
+// tinyLFU is W-TinyLFU: new keys enter a small LRU window, and a key leaving the
+// window is admitted to the main segmented LRU only if it has been requested more
+// often than the key it would replace. The key being written is never the one
+// leaving the window, so the frequency contest only decides between older entries.
+// Frequencies are kept in a count-min sketch that is halved periodically so old
+// popularity fades.
+type tinyLFU struct {
+	window    *lru
+	probation *lru
+	protected *lru
+	sketch    *countMinSketch
+}
+
+func newTinyLFU(capacity int) *tinyLFU {
+	return &tinyLFU{
+		window:    newLRU(),
+		probation: newLRU(),
+		protected: newLRU(),
+		sketch:    newCountMinSketch(capacity),
+	}
+}
+
+func (t *tinyLFU) size() int {
+	return t.window.len() + t.probation.len() + t.protected.len()
+}
+
+func (t *tinyLFU) windowCapacity() int {
+	return max(1, t.size()*tinyLFUWindowPercent/100)
+}
+
+func (t *tinyLFU) add(key string, full bool) {
+	// A key already held is counted by access alone, so a re-Set is one request.
+	if t.window.contains(key) || t.probation.contains(key) || t.protected.contains(key) {
+		t.access(key)
+		return
+	}
+	t.sketch.increment(key)
+
+	t.window.add(key, full)
+	if full {
+		return
+	}
+
+	// While there is room, keys leaving the window go to main without a contest.
+	for t.window.len() > t.windowCapacity() {
+		candidate, _ := t.window.victim(key)
+		t.probation.add(candidate, false)
+	}
+}
+
+func (t *tinyLFU) access(key string) {
+	t.sketch.increment(key)
+
+	switch {
+	case t.window.contains(key):
+		t.window.access(key)
+	case t.protected.contains(key):
+		t.protected.access(key)
+	case t.probation.contains(key):
+		t.probation.remove(key)
+		t.protected.add(key, false)
+
+		mainSize := t.probation.len() + t.protected.len()
+		for t.protected.len() > mainSize*tinyLFUProtectedPercent/100 {
+			demoted, _ := t.protected.victim("")
+			t.probation.add(demoted, false)
+		}
+	}
+}
+
+func (t *tinyLFU) remove(key string) {
+	t.window.remove(key)
+	t.probation.remove(key)
+	t.protected.remove(key)
+}
+
+func (t *tinyLFU) mainVictim(keep string) (string, *lru, bool) {
+	if key, ok := t.probation.oldestExcept(keep); ok {
+		return key, t.probation, true
+	}
+	if key, ok := t.protected.oldestExcept(keep); ok {
+		return key, t.protected, true
+	}
+	return "", nil, false
+}
+
+func (t *tinyLFU) victim(keep string) (string, bool) {
+	candidate, hasCandidate := t.window.oldestExcept(keep)
+	victim, segment, hasVictim := t.mainVictim(keep)
+
+	switch {
+	case !hasCandidate && !hasVictim:
+		return "", false
+	case !hasVictim:
+		t.window.remove(candidate)
+		return candidate, true
+	case !hasCandidate || t.window.len() <= t.windowCapacity():
+		segment.remove(victim)
+		return victim, true
+	}
+
+	if t.sketch.estimate(candidate) > t.sketch.estimate(victim) {
+		segment.remove(victim)
+		t.window.remove(candidate)
+		t.probation.add(candidate, false)
+		return victim, true
+	}
+
+	t.window.remove(candidate)
+	return candidate, true
+}
+
+// countMinSketch estimates key frequencies in fixed memory.
+type countMinSketch struct {
+	seed       maphash.Seed
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	additions  int
+	sampleSize int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := sketchMinWidth
+	for width < capacity {
+		width <<= 1
+	}
+
+	sketch := &countMinSketch{
+		seed:       maphash.MakeSeed(),
+		mask:       uint64(width - 1),
+		sampleSize: 10 * width,
+	}
+	for i := range sketch.rows {
+		sketch.rows[i] = make([]uint8, width)
+	}
+
+	return sketch
+}
+
+func (s *countMinSketch) indexes(key string) [sketchDepth]uint64 {
+	hash := maphash.String(s.seed, key)
+	h1, h2 := hash&0xffffffff, hash>>32
+
+	var indexes [sketchDepth]uint64
+	for i := range indexes {
+		indexes[i] = (h1 + uint64(i)*h2) & s.mask
+	}
+	return indexes
+}
+
+func (s *countMinSketch) increment(key string) {
+	for i, index := range s.indexes(key) {
+		if s.rows[i][index] < 15 {
+			s.rows[i][index]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.reset()
+	}
+}
+
+func (s *countMinSketch) estimate(key string) uint8 {
+	estimate := uint8(15)
+	for i, index := range s.indexes(key) {
+		estimate = min(estimate, s.rows[i][index])
+	}
+	return estimate
+}
+
+func (s *countMinSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
@@ -0,0 +1,698 @@
+package order
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/codec"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+
+	redisLib "github.com/redis/go-redis/v9"
+)
+
+const (
+	orderKeyPrefix   = "order:"
+	indexKeyPrefix   = "index:"
+	missingKeyPrefix = "missing:"
+	warmUpKey        = "warm-up"
+
+	adminTimeout = 30 * time.Second
+)
+
+// Cache keeps orders in Redis so every API replica shares one warm cache.
+// Redis errors are logged and treated as misses: the cache is never the source of truth.
+type Cache struct {
+	log         appPorts.Logger
+	client      redisLib.UniversalClient
+	initializer ports.CacheInitializer
+	stopChan    chan struct{}
+	stopOnce    sync.Once
+
+	prefix          string
+	ttl             time.Duration
+	staleWindow     time.Duration
+	negativeTTL     time.Duration
+	cleanupInterval time.Duration
+	opTimeout       time.Duration
+	warmUpCount     int
+	warmUpStrategy  string
+	warmedUp        atomic.Bool
+	warmed          chan struct{}
+	warmedOnce      sync.Once
+
+	mu    sync.Mutex
+	reads map[string]int64
+	stats cacheCounters
+}
+
+type cacheCounters struct {
+	hits        atomic.Int64
+	misses      atomic.Int64
+	missingHits atomic.Int64
+	errors      atomic.Int64
+}
+
+func NewClient(cfg *config.CacheRedis) *redisLib.Client {
+	return redisLib.NewClient(&redisLib.Options{
+		Addr:         cfg.Address,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.OperationTimeout,
+		WriteTimeout: cfg.OperationTimeout,
+	})
+}
+
+// NewCache takes the client from the caller so it can point at any Redis-compatible
+// server, including an in-process stand-in.
+func NewCache(
+	log appPorts.Logger,
+	cfg *config.Cache,
+	client redisLib.UniversalClient,
+	initializer ports.CacheInitializer,
+) *Cache {
+	return &Cache{
+		log:         log,
+		client:      client,
+		initializer: initializer,
+		stopChan:    make(chan struct{}),
+
+		prefix:          cfg.Redis.KeyPrefix,
+		ttl:             cfg.TTL,
+		staleWindow:     cfg.StaleWhileRevalidate,
+		negativeTTL:     cfg.NegativeTTL,
+		cleanupInterval: cfg.CleanupInterval,
+		opTimeout:       cfg.Redis.OperationTimeout,
+		warmUpCount:     cfg.WarmUp.Count,
+		warmUpStrategy:  cfg.WarmUp.Strategy,
+		warmed:          make(chan struct{}),
+
+		reads: make(map[string]int64),
+	}
+}
+
+func (c *Cache) orderKey(orderUID string) string {
+	return c.prefix + orderKeyPrefix + orderUID
+}
+
+func (c *Cache) indexKey(key string) string {
+	return c.prefix + indexKeyPrefix + key
+}
+
+func (c *Cache) missingKey(orderUID string) string {
+	return c.prefix + missingKeyPrefix + orderUID
+}
+
+func (c *Cache) opContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.opTimeout)
+}
+
+func (c *Cache) failed(op string, err error) {
+	c.stats.errors.Add(1)
+	c.log.Warn("Redis cache operation failed", "operation", op, "error", err.Error())
+}
+
+// encodeEntry prefixes the encoded order with the time it stops being fresh.
+// The Redis TTL also covers the stale window, after which Redis drops the key.
+func encodeEntry(order *model.Order, freshUntil time.Time) []byte {
+	buf := binary.AppendVarint(make([]byte, 0, 512), freshUntil.UnixMilli())
+	return codec.AppendOrder(buf, order)
+}
+
+func decodeEntry(data []byte) (*model.Order, time.Time, error) {
+	freshUntil, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, time.Time{}, codec.ErrTruncated
+	}
+
+	order, _, err := codec.DecodeOrder(data[n:])
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return order, time.UnixMilli(freshUntil), nil
+}
+
+func (c *Cache) setOrder(
+	ctx context.Context,
+	pipe redisLib.Pipeliner,
+	orderUID string,
+	order *model.Order,
+	now time.Time,
+) {
+	pipe.Set(ctx, c.orderKey(orderUID), encodeEntry(order, now.Add(c.ttl)), c.ttl+c.staleWindow)
+	pipe.Del(ctx, c.missingKey(orderUID))
+}
+
+func (c *Cache) Set(orderUID string, order *model.Order) {
+	const op = "redis.Cache.Set"
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	_, err := c.client.Pipelined(ctx, func(pipe redisLib.Pipeliner) error {
+		c.setOrder(ctx, pipe, orderUID, order, time.Now())
+		return nil
+	})
+	if err != nil {
+		c.failed(op, fmt.Errorf("set %s: %w", orderUID, err))
+	}
+}
+
+// get returns the cached order and whether it is still fresh.
+func (c *Cache) get(op, orderUID string) (*model.Order, bool) {
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.orderKey(orderUID)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redisLib.Nil) {
+			c.failed(op, err)
+		}
+		return nil, false
+	}
+
+	order, freshUntil, err := decodeEntry(data)
+	if err != nil {
+		c.failed(op, fmt.Errorf("decode %s: %w", orderUID, err))
+		return nil, false
+	}
+
+	return order, time.Now().Before(freshUntil)
+}
+
+func (c *Cache) Get(orderUID string) *model.Order {
+	const op = "redis.Cache.Get"
+
+	order, fresh := c.get(op, orderUID)
+	if order == nil || !fresh {
+		c.stats.misses.Add(1)
+		return nil
+	}
+
+	c.stats.hits.Add(1)
+	c.recordRead(orderUID)
+
+	return order
+}
+
+func (c *Cache) GetStale(orderUID string) *model.Order {
+	const op = "redis.Cache.GetStale"
+
+	if c.staleWindow <= 0 {
+		return nil
+	}
+
+	order, _ := c.get(op, orderUID)
+
+	return order
+}
+
+func (c *Cache) Peek(orderUID string) *model.Order {
+	const op = "redis.Cache.Peek"
+
+	order, _ := c.get(op, orderUID)
+
+	return order
+}
+
+func (c *Cache) SetMissing(orderUID string) {
+	const op = "redis.Cache.SetMissing"
+
+	if c.negativeTTL <= 0 {
+		return
+	}
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	// The order may have been stored while it was being looked up.
+	exists, err := c.client.Exists(ctx, c.orderKey(orderUID)).Result()
+	if err != nil {
+		c.failed(op, err)
+		return
+	}
+	if exists > 0 {
+		return
+	}
+
+	if err = c.client.Set(ctx, c.missingKey(orderUID), 1, c.negativeTTL).Err(); err != nil {
+		c.failed(op, err)
+	}
+}
+
+func (c *Cache) IsMissing(orderUID string) bool {
+	const op = "redis.Cache.IsMissing"
+
+	if c.negativeTTL <= 0 {
+		return false
+	}
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	exists, err := c.client.Exists(ctx, c.missingKey(orderUID)).Result()
+	if err != nil {
+		c.failed(op, err)
+		return false
+	}
+	if exists == 0 {
+		return false
+	}
+
+	c.stats.missingHits.Add(1)
+
+	return true
+}
+
+// SetIndex stores the orders and the list of their UIDs under the secondary key
+// in one pipeline.
+func (c *Cache) SetIndex(key string, orders []*model.Order) {
+	const op = "redis.Cache.SetIndex"
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	now := time.Now()
+	orderUIDs := make([]string, len(orders))
+
+	_, err := c.client.Pipelined(ctx, func(pipe redisLib.Pipeliner) error {
+		for i, order := range orders {
+			orderUIDs[i] = order.OrderUID
+			c.setOrder(ctx, pipe, order.OrderUID, order, now)
+		}
+		pipe.Set(ctx, c.indexKey(key), encodeUIDs(orderUIDs), c.ttl)
+		return nil
+	})
+	if err != nil {
+		c.failed(op, fmt.Errorf("set index %s: %w", key, err))
+	}
+}
+
+// GetIndex resolves the secondary key and fetches all its orders with one MGET.
+// It returns nil if the key or any of its orders is missing or expired.
+func (c *Cache) GetIndex(key string) []*model.Order {
+	const op = "redis.Cache.GetIndex"
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.indexKey(key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redisLib.Nil) {
+			c.failed(op, err)
+		}
+		return nil
+	}
+
+	orderUIDs, err := decodeUIDs(data)
+	if err != nil {
+		c.failed(op, fmt.Errorf("decode index %s: %w", key, err))
+		return nil
+	}
+
+	keys := make([]string, len(orderUIDs))
+	for i, orderUID := range orderUIDs {
+		keys[i] = c.orderKey(orderUID)
+	}
+
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.failed(op, err)
+		return nil
+	}
+
+	now := time.Now()
+	orders := make([]*model.Order, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		order, freshUntil, err := decodeEntry([]byte(raw))
+		if err != nil || now.After(freshUntil) {
+			return nil
+		}
+		orders = append(orders, order)
+	}
+
+	for _, orderUID := range orderUIDs {
+		c.recordRead(orderUID)
+	}
+
+	return orders
+}
+
+func (c *Cache) DeleteIndex(keys ...string) {
+	const op = "redis.Cache.DeleteIndex"
+
+	if len(keys) == 0 {
+		return
+	}
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	indexKeys := make([]string, len(keys))
+	for i, key := range keys {
+		indexKeys[i] = c.indexKey(key)
+	}
+
+	if err := c.client.Del(ctx, indexKeys...).Err(); err != nil {
+		c.failed(op, err)
+	}
+}
+
+func encodeUIDs(orderUIDs []string) []byte {
+	buf := binary.AppendUvarint(nil, uint64(len(orderUIDs)))
+	for _, orderUID := range orderUIDs {
+		buf = binary.AppendUvarint(buf, uint64(len(orderUID)))
+		buf = append(buf, orderUID...)
+	}
+	return buf
+}
+
+func decodeUIDs(data []byte) ([]string, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, codec.ErrTruncated
+	}
+	data = data[n:]
+
+	orderUIDs := make([]string, 0, count)
+	for range count {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return nil, codec.ErrTruncated
+		}
+		orderUIDs = append(orderUIDs, string(data[n:n+int(size)]))
+		data = data[n+int(size):]
+	}
+
+	return orderUIDs, nil
+}
+
+func (c *Cache) recordRead(orderUID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reads[orderUID]++
+}
+
+func (c *Cache) flushReads(ctx context.Context) {
+	const op = "redis.Cache.flushReads"
+
+	c.mu.Lock()
+	reads := c.reads
+	c.reads = make(map[string]int64)
+	c.mu.Unlock()
+
+	if err := c.initializer.RecordOrderReads(ctx, reads); err != nil {
+		c.log.Error("Failed to record order reads", "operation", op, "error", err.Error())
+	}
+}
+
+func (c *Cache) Stats() model.CacheStats {
+	hits, misses := c.stats.hits.Load(), c.stats.misses.Load()
+
+	return model.CacheStats{
+		Policy:      "redis",
+		Hits:        hits,
+		Misses:      misses,
+		HitRatio:    model.HitRatio(hits, misses),
+		MissingHits: c.stats.missingHits.Load(),
+	}
+}
+
+const scanBatch = 1000
+
+// scan returns every key matching pattern, iterating with SCAN so Redis is never blocked.
+func (c *Cache) scan(ctx context.Context, pattern string) ([]string, error) {
+	var (
+		keys   []string
+		cursor uint64
+	)
+	for {
+		batch, next, err := c.client.Scan(ctx, cursor, pattern, scanBatch).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+		if cursor = next; cursor == 0 {
+			return keys, nil
+		}
+	}
+}
+
+// Keys returns the UIDs of all cached orders in sorted order.
+func (c *Cache) Keys() []string {
+	const op = "redis.Cache.Keys"
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	keys, err := c.scan(ctx, c.orderKey("*"))
+	if err != nil {
+		c.failed(op, err)
+		return nil
+	}
+
+	orderUIDs := make([]string, len(keys))
+	for i, key := range keys {
+		orderUIDs[i] = strings.TrimPrefix(key, c.orderKey(""))
+	}
+	slices.Sort(orderUIDs)
+
+	return orderUIDs
+}
+
+// Delete evicts a single order and reports whether it was cached.
+func (c *Cache) Delete(orderUID string) bool {
+	const op = "redis.Cache.Delete"
+
+	ctx, cancel := c.opContext()
+	defer cancel()
+
+	var deleted *redisLib.IntCmd
+	_, err := c.client.Pipelined(ctx, func(pipe redisLib.Pipeliner) error {
+		deleted = pipe.Del(ctx, c.orderKey(orderUID))
+		pipe.Del(ctx, c.missingKey(orderUID))
+		return nil
+	})
+	if err != nil {
+		c.failed(op, err)
+		return false
+	}
+
+	// Only the order key counts: clearing a negative entry evicts nothing.
+	return deleted.Val() > 0
+}
+
+// Flush drops every key under the cache prefix and returns how many orders were cached.
+func (c *Cache) Flush() int {
+	const op = "redis.Cache.Flush"
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	keys, err := c.scan(ctx, c.prefix+"*")
+	if err != nil {
+		c.failed(op, err)
+		return 0
+	}
+
+	flushed := 0
+	for batch := range slices.Chunk(keys, scanBatch) {
+		if err = c.client.Del(ctx, batch...).Err(); err != nil {
+			c.failed(op, err)
+			return flushed
+		}
+		for _, key := range batch {
+			if strings.HasPrefix(key, c.orderKey("")) {
+				flushed++
+			}
+		}
+	}
+
+	return flushed
+}
+
+// WarmUp loads the latest limit orders from the initializer, bypassing the
+// once-per-TTL lock used at start-up.
+func (c *Cache) WarmUp(ctx context.Context, limit int) (int, error) {
+	const op = "redis.Cache.WarmUp"
+
+	orders, err := c.initializer.GetOrdersForCache(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	if _, err = c.client.Pipelined(ctx, func(pipe redisLib.Pipeliner) error {
+		for _, order := range orders {
+			c.setOrder(ctx, pipe, order.OrderUID, order, now)
+		}
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return len(orders), nil
+}
+
+// warmUp loads orders from the initializer into Redis. Replicas share the cache, so
+// only the first one to start within a TTL does the work.
+func (c *Cache) warmUp(ctx context.Context) error {
+	const op = "redis.Cache.warmUp"
+
+	if c.warmUpCount == 0 {
+		return nil
+	}
+
+	acquired, err := c.client.SetNX(ctx, c.prefix+warmUpKey, 1, c.ttl).Result()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !acquired {
+		c.log.Info("Cache already warmed by another replica", "operation", op)
+		return nil
+	}
+
+	var orders []*model.Order
+	if c.warmUpStrategy == "most_read" {
+		orders, err = c.initializer.GetMostReadOrdersForCache(ctx, c.warmUpCount)
+	} else {
+		orders, err = c.initializer.GetOrdersForCache(ctx, c.warmUpCount)
+	}
+	if err != nil {
+		c.releaseWarmUp(ctx)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	if _, err = c.client.Pipelined(ctx, func(pipe redisLib.Pipeliner) error {
+		for _, order := range orders {
+			c.setOrder(ctx, pipe, order.OrderUID, order, now)
+		}
+		return nil
+	}); err != nil {
+		c.releaseWarmUp(ctx)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("Cache initialization",
+		"operation", op,
+		"strategy", c.warmUpStrategy,
+		"orders_count", len(orders),
+	)
+
+	return nil
+}
+
+// releaseWarmUp drops the warm-up lock after a failed warm-up, so the next start of
+// this or another replica retries it instead of waiting out the TTL with an empty cache.
+func (c *Cache) releaseWarmUp(ctx context.Context) {
+	if err := c.client.Del(context.WithoutCancel(ctx), c.prefix+warmUpKey).Err(); err != nil {
+		c.log.Error("Failed to release cache warm-up lock", "operation", "redis.Cache.warmUp", "error", err.Error())
+	}
+}
+
+func (c *Cache) Run(ctx context.Context) error {
+	const op = "redis.Cache.Run"
+
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.warmUp(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	c.warmedUp.Store(true)
+	c.warmedOnce.Do(func() { close(c.warmed) })
+
+	flushTicker := time.NewTicker(c.cleanupInterval)
+	defer flushTicker.Stop()
+
+	for {
+		select {
+		case <-flushTicker.C:
+			c.flushReads(ctx)
+		case <-c.stopChan:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// WarmedUp is closed once the start-up warm-up has finished, by this replica or another.
+func (c *Cache) WarmedUp() <-chan struct{} {
+	return c.warmed
+}
+
+// CachedOrders returns up to limit fresh orders currently held in Redis, in no
+// particular order. It lets a local tier start from what is already shared.
+func (c *Cache) CachedOrders(ctx context.Context, limit int) ([]*model.Order, error) {
+	const op = "redis.Cache.CachedOrders"
+
+	keys, err := c.scan(ctx, c.orderKey("*"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	keys = keys[:min(limit, len(keys))]
+
+	now := time.Now()
+	orders := make([]*model.Order, 0, len(keys))
+	for batch := range slices.Chunk(keys, scanBatch) {
+		values, err := c.client.MGet(ctx, batch...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, value := range values {
+			raw, ok := value.(string)
+			if !ok {
+				continue
+			}
+			order, freshUntil, err := decodeEntry([]byte(raw))
+			if err != nil || now.After(freshUntil) {
+				continue
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	return orders, nil
+}
+
+// HealthCheck reports the cache ready once the start-up warm-up has finished
+// and while Redis answers pings.
+func (c *Cache) HealthCheck(ctx context.Context) appPorts.Health {
+	if !c.warmedUp.Load() {
+		return appPorts.NotReady("warm-up in progress")
+	}
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return appPorts.NotReady("ping failed: " + err.Error())
+	}
+	return appPorts.Up()
+}
+
+func (c *Cache) Shutdown(ctx context.Context) error {
+	var err error
+	c.stopOnce.Do(func() {
+		c.flushReads(ctx)
+		close(c.stopChan)
+		err = c.client.Close()
+	})
+	return err
+}
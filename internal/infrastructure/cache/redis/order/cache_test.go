@@ -0,0 +1,272 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/service/mapper"
+
+	"github.com/alicebob/miniredis/v2"
+	redisLib "github.com/redis/go-redis/v9"
+)
+
+// storage stands in for the repository the cache warms up from.
+type storage struct {
+	orders []*model.Order
+	loads  atomic.Int64
+}
+
+func (s *storage) GetOrdersForCache(_ context.Context, limit int) ([]*model.Order, error) {
+	s.loads.Add(1)
+	return s.orders[:min(limit, len(s.orders))], nil
+}
+
+func (s *storage) GetMostReadOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
+	return s.GetOrdersForCache(ctx, limit)
+}
+
+func (s *storage) RecordOrderReads(context.Context, map[string]int64) error {
+	return nil
+}
+
+func testConfig() *config.Cache {
+	return &config.Cache{
+		TTL:             time.Minute,
+		CleanupInterval: time.Minute,
+		NegativeTTL:     time.Minute,
+		WarmUp:          config.CacheWarmUp{Count: 10, Strategy: "latest"},
+		Redis:           config.CacheRedis{KeyPrefix: "test:", OperationTimeout: time.Second},
+	}
+}
+
+func newTestCache(t *testing.T, server *miniredis.Miniredis, cfg *config.Cache, initializer *storage) *Cache {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := redisLib.NewClient(&redisLib.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewCache(log, cfg, client, initializer)
+}
+
+func generateOrders(count int) []*model.Order {
+	generator := mock.NewMockGenerator()
+	orders := make([]*model.Order, count)
+	for i := range orders {
+		orders[i] = mapper.OrderFromDTO(generator.GenerateOrder())
+	}
+	return orders
+}
+
+func TestSetGet(t *testing.T) {
+	cache := newTestCache(t, miniredis.RunT(t), testConfig(), &storage{})
+	order := generateOrders(1)[0]
+
+	cache.Set(order.OrderUID, order)
+
+	got := cache.Get(order.OrderUID)
+	if got == nil {
+		t.Fatal("Get returned nil after Set")
+	}
+	if got.OrderUID != order.OrderUID || len(got.Items) != len(order.Items) {
+		t.Fatalf("Get = %+v, want %+v", got, order)
+	}
+	if cache.Get("unknown") != nil {
+		t.Fatal("Get of an unknown order returned an order")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("hits, misses = %d, %d, want 1, 1", stats.Hits, stats.Misses)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	server := miniredis.RunT(t)
+	cfg := testConfig()
+	cfg.TTL = 50 * time.Millisecond
+	cfg.StaleWhileRevalidate = time.Minute
+	cache := newTestCache(t, server, cfg, &storage{})
+	order := generateOrders(1)[0]
+
+	cache.Set(order.OrderUID, order)
+	if ttl := server.TTL(cache.orderKey(order.OrderUID)); ttl != cfg.TTL+cfg.StaleWhileRevalidate {
+		t.Fatalf("redis TTL = %s, want %s", ttl, cfg.TTL+cfg.StaleWhileRevalidate)
+	}
+
+	time.Sleep(2 * cfg.TTL)
+	if cache.Get(order.OrderUID) != nil {
+		t.Fatal("Get returned an expired order")
+	}
+	if cache.GetStale(order.OrderUID) == nil {
+		t.Fatal("GetStale returned nil within the stale window")
+	}
+
+	server.FastForward(cfg.TTL + cfg.StaleWhileRevalidate)
+	if cache.GetStale(order.OrderUID) != nil || cache.Peek(order.OrderUID) != nil {
+		t.Fatal("order is still cached after the stale window")
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	cache := newTestCache(t, miniredis.RunT(t), testConfig(), &storage{})
+	orders := generateOrders(2)
+
+	cache.SetIndex("track", orders)
+	if got := cache.GetIndex("track"); len(got) != len(orders) {
+		t.Fatalf("GetIndex returned %d orders, want %d", len(got), len(orders))
+	}
+
+	if !cache.Delete(orders[0].OrderUID) {
+		t.Fatal("Delete of a cached order reported false")
+	}
+	if cache.Delete(orders[0].OrderUID) {
+		t.Fatal("second Delete reported true")
+	}
+	if cache.Get(orders[0].OrderUID) != nil {
+		t.Fatal("Get returned a deleted order")
+	}
+	if cache.GetIndex("track") != nil {
+		t.Fatal("GetIndex returned an index with a deleted order")
+	}
+
+	cache.SetIndex("track", orders)
+	cache.DeleteIndex("track")
+	if cache.GetIndex("track") != nil {
+		t.Fatal("GetIndex returned a deleted index")
+	}
+}
+
+func TestMissing(t *testing.T) {
+	cache := newTestCache(t, miniredis.RunT(t), testConfig(), &storage{})
+	order := generateOrders(1)[0]
+
+	cache.SetMissing(order.OrderUID)
+	if !cache.IsMissing(order.OrderUID) {
+		t.Fatal("IsMissing = false after SetMissing")
+	}
+
+	cache.Set(order.OrderUID, order)
+	if cache.IsMissing(order.OrderUID) {
+		t.Fatal("Set did not clear the negative entry")
+	}
+
+	cache.SetMissing(order.OrderUID)
+	if cache.IsMissing(order.OrderUID) {
+		t.Fatal("SetMissing marked a cached order as missing")
+	}
+}
+
+func TestDeleteOfMissingReportsFalse(t *testing.T) {
+	cache := newTestCache(t, miniredis.RunT(t), testConfig(), &storage{})
+
+	cache.SetMissing("gone")
+	if cache.Delete("gone") {
+		t.Fatal("Delete of a negative entry reported true")
+	}
+	if cache.IsMissing("gone") {
+		t.Fatal("Delete left the negative entry behind")
+	}
+}
+
+func TestShutdownTwice(t *testing.T) {
+	cache := newTestCache(t, miniredis.RunT(t), testConfig(), &storage{})
+
+	if err := cache.Shutdown(context.Background()); err != nil {
+		t.Fatalf("first Shutdown: %v", err)
+	}
+	if err := cache.Shutdown(context.Background()); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+}
+
+func TestRedisDownIsAMiss(t *testing.T) {
+	server := miniredis.RunT(t)
+	cfg := testConfig()
+	cfg.Redis.OperationTimeout = 100 * time.Millisecond
+	cache := newTestCache(t, server, cfg, &storage{})
+	order := generateOrders(1)[0]
+
+	cache.Set(order.OrderUID, order)
+	server.Close()
+
+	if cache.Get(order.OrderUID) != nil {
+		t.Fatal("Get returned an order while Redis is down")
+	}
+	if cache.HealthCheck(context.Background()).Ready() {
+		t.Fatal("cache is ready while Redis is down")
+	}
+}
+
+// failPipelines fails every pipeline while single commands still reach Redis.
+type failPipelines struct{}
+
+func (failPipelines) DialHook(next redisLib.DialHook) redisLib.DialHook {
+	return next
+}
+
+func (failPipelines) ProcessHook(next redisLib.ProcessHook) redisLib.ProcessHook {
+	return next
+}
+
+func (failPipelines) ProcessPipelineHook(redisLib.ProcessPipelineHook) redisLib.ProcessPipelineHook {
+	return func(context.Context, []redisLib.Cmder) error {
+		return errors.New("pipeline failed")
+	}
+}
+
+func TestWarmUpReleasesLockOnWriteFailure(t *testing.T) {
+	server := miniredis.RunT(t)
+	initializer := &storage{orders: generateOrders(5)}
+	cache := newTestCache(t, server, testConfig(), initializer)
+	// The connection is set up first: go-redis initialises new connections with a pipeline.
+	if err := cache.client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	cache.client.AddHook(failPipelines{})
+
+	if err := cache.warmUp(context.Background()); err == nil {
+		t.Fatal("warmUp succeeded while writes failed")
+	}
+	if server.Exists(cache.prefix + warmUpKey) {
+		t.Fatal("warm-up lock is still held after a failed warm-up")
+	}
+
+	retry := newTestCache(t, server, testConfig(), initializer)
+	if err := retry.warmUp(context.Background()); err != nil {
+		t.Fatalf("warmUp: %v", err)
+	}
+	if loads := initializer.loads.Load(); loads != 2 {
+		t.Fatalf("storage loaded %d times, want 2", loads)
+	}
+}
+
+func TestWarmUpOncePerTTL(t *testing.T) {
+	server := miniredis.RunT(t)
+	initializer := &storage{orders: generateOrders(5)}
+	first := newTestCache(t, server, testConfig(), initializer)
+	second := newTestCache(t, server, testConfig(), initializer)
+
+	for _, cache := range []*Cache{first, second} {
+		if err := cache.warmUp(context.Background()); err != nil {
+			t.Fatalf("warmUp: %v", err)
+		}
+	}
+
+	if loads := initializer.loads.Load(); loads != 1 {
+		t.Fatalf("storage loaded %d times, want 1", loads)
+	}
+	for _, order := range initializer.orders {
+		if second.Get(order.OrderUID) == nil {
+			t.Fatalf("order %s was not warmed up", order.OrderUID)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	memoryCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	redisCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/redis/order"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Cache puts the in-process cache in front of Redis: reads try the local tier first
+// and fill it from Redis, writes go to both. Negative entries live only in Redis so
+// an order created through any replica clears them for all. Only Redis warms up from
+// storage; the local tier is seeded from Redis, see LocalInitializer.
+type Cache struct {
+	local  *memoryCache.Cache
+	remote *redisCache.Cache
+}
+
+func NewCache(local *memoryCache.Cache, remote *redisCache.Cache) *Cache {
+	return &Cache{
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (c *Cache) Set(orderUID string, order *model.Order) {
+	c.local.Set(orderUID, order)
+	c.remote.Set(orderUID, order)
+}
+
+func (c *Cache) Get(orderUID string) *model.Order {
+	if order := c.local.Get(orderUID); order != nil {
+		return order
+	}
+
+	order := c.remote.Get(orderUID)
+	if order != nil {
+		c.local.Set(orderUID, order)
+	}
+
+	return order
+}
+
+func (c *Cache) GetStale(orderUID string) *model.Order {
+	if order := c.local.GetStale(orderUID); order != nil {
+		return order
+	}
+	return c.remote.GetStale(orderUID)
+}
+
+func (c *Cache) Peek(orderUID string) *model.Order {
+	if order := c.local.Peek(orderUID); order != nil {
+		return order
+	}
+	return c.remote.Peek(orderUID)
+}
+
+func (c *Cache) SetMissing(orderUID string) {
+	c.remote.SetMissing(orderUID)
+}
+
+func (c *Cache) IsMissing(orderUID string) bool {
+	return c.remote.IsMissing(orderUID)
+}
+
+func (c *Cache) SetIndex(key string, orders []*model.Order) {
+	c.local.SetIndex(key, orders)
+	c.remote.SetIndex(key, orders)
+}
+
+func (c *Cache) GetIndex(key string) []*model.Order {
+	if orders := c.local.GetIndex(key); orders != nil {
+		return orders
+	}
+
+	orders := c.remote.GetIndex(key)
+	if orders != nil {
+		c.local.SetIndex(key, orders)
+	}
+
+	return orders
+}
+
+func (c *Cache) DeleteIndex(keys ...string) {
+	c.local.DeleteIndex(keys...)
+	c.remote.DeleteIndex(keys...)
+}
+
+// Stats reports each tier under Tiers. The top-level counters describe lookups
+// through both: Redis is only asked after a local miss, so a lookup hits if either
+// tier hits and misses only if Redis misses too.
+func (c *Cache) Stats() model.CacheStats {
+	local := c.local.Stats()
+	remote := c.remote.Stats()
+
+	stats := local
+	stats.Policy += "+redis"
+	stats.Hits = local.Hits + remote.Hits
+	stats.Misses = remote.Misses
+	stats.HitRatio = model.HitRatio(stats.Hits, stats.Misses)
+	stats.MissingHits = remote.MissingHits
+	stats.Tiers = map[string]model.CacheStats{
+		"memory": local,
+		"redis":  remote,
+	}
+
+	return stats
+}
+
+func (c *Cache) Keys() []string {
+	return c.remote.Keys()
+}
+
+func (c *Cache) Delete(orderUID string) bool {
+	local := c.local.Delete(orderUID)
+	remote := c.remote.Delete(orderUID)
+	return local || remote
+}
+
+func (c *Cache) Flush() int {
+	c.local.Flush()
+	return c.remote.Flush()
+}
+
+// WarmUp loads storage into Redis, then fills the local tier from Redis.
+func (c *Cache) WarmUp(ctx context.Context, limit int) (int, error) {
+	if _, err := c.remote.WarmUp(ctx, limit); err != nil {
+		return 0, err
+	}
+	return c.local.WarmUp(ctx, limit)
+}
+
+// Run starts the local tier after Redis has warmed up, so its own warm-up is
+// seeded with what Redis holds by then.
+func (c *Cache) Run(ctx context.Context) error {
+	errGroup, ctx := errgroup.WithContext(ctx)
+	errGroup.Go(func() error { return c.remote.Run(ctx) })
+	errGroup.Go(func() error {
+		select {
+		case <-c.remote.WarmedUp():
+		case <-ctx.Done():
+			return nil
+		}
+		return c.local.Run(ctx)
+	})
+	return errGroup.Wait()
+}
+
+func (c *Cache) HealthCheck(ctx context.Context) appPorts.Health {
+	if health := c.remote.HealthCheck(ctx); !health.Ready() {
+		health.Detail = "redis: " + health.Detail
+		return health
+	}
+	return c.local.HealthCheck(ctx)
+}
+
+func (c *Cache) Shutdown(ctx context.Context) error {
+	return errors.Join(
+		c.local.Shutdown(ctx),
+		c.remote.Shutdown(ctx),
+	)
+}
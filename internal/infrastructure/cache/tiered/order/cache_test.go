@@ -0,0 +1,168 @@
+package order
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	memoryCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	redisCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/redis/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/service/mapper"
+
+	"github.com/alicebob/miniredis/v2"
+	redisLib "github.com/redis/go-redis/v9"
+)
+
+// storage stands in for the repository the cache warms up from.
+type storage struct {
+	orders []*model.Order
+	loads  atomic.Int64
+}
+
+func (s *storage) GetOrdersForCache(_ context.Context, limit int) ([]*model.Order, error) {
+	s.loads.Add(1)
+	return s.orders[:min(limit, len(s.orders))], nil
+}
+
+func (s *storage) GetMostReadOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
+	return s.GetOrdersForCache(ctx, limit)
+}
+
+func (s *storage) RecordOrderReads(context.Context, map[string]int64) error {
+	return nil
+}
+
+type tiers struct {
+	cache  *Cache
+	local  *memoryCache.Cache
+	remote *redisCache.Cache
+	server *miniredis.Miniredis
+}
+
+// newTestCache wires the tiers the way the cache factory does.
+func newTestCache(t *testing.T, initializer *storage) *tiers {
+	t.Helper()
+
+	cfg := &config.Cache{
+		TTL:             time.Minute,
+		CleanupInterval: time.Minute,
+		Shards:          4,
+		WarmUp:          config.CacheWarmUp{Count: 10, Strategy: "latest"},
+		Eviction:        config.CacheEviction{Policy: "w-tinylfu"},
+		Redis:           config.CacheRedis{KeyPrefix: "test:", OperationTimeout: 100 * time.Millisecond},
+	}
+
+	server := miniredis.RunT(t)
+	client := redisLib.NewClient(&redisLib.Options{Addr: server.Addr()})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	remote := redisCache.NewCache(log, cfg, client, initializer)
+	local := memoryCache.NewCache(log, cfg, LocalInitializer(remote, initializer))
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &tiers{
+		cache:  NewCache(local, remote),
+		local:  local,
+		remote: remote,
+		server: server,
+	}
+}
+
+func generateOrders(count int) []*model.Order {
+	generator := mock.NewMockGenerator()
+	orders := make([]*model.Order, count)
+	for i := range orders {
+		orders[i] = mapper.OrderFromDTO(generator.GenerateOrder())
+	}
+	return orders
+}
+
+func TestGetFallsBackToRedis(t *testing.T) {
+	c := newTestCache(t, &storage{})
+	order := generateOrders(1)[0]
+
+	c.remote.Set(order.OrderUID, order)
+	if c.local.Peek(order.OrderUID) != nil {
+		t.Fatal("order is in the local tier before it was read")
+	}
+
+	if c.cache.Get(order.OrderUID) == nil {
+		t.Fatal("Get did not fall back to Redis")
+	}
+	if c.local.Peek(order.OrderUID) == nil {
+		t.Fatal("Get did not fill the local tier")
+	}
+
+	stats := c.cache.Stats()
+	local, remote := stats.Tiers["memory"], stats.Tiers["redis"]
+	if local.Misses != 1 || remote.Hits != 1 {
+		t.Fatalf("local misses, redis hits = %d, %d, want 1, 1", local.Misses, remote.Hits)
+	}
+	if stats.Hits != 1 || stats.Misses != 0 {
+		t.Fatalf("hits, misses = %d, %d, want 1, 0", stats.Hits, stats.Misses)
+	}
+}
+
+func TestLocalTierServesWhileRedisIsDown(t *testing.T) {
+	c := newTestCache(t, &storage{})
+	order := generateOrders(1)[0]
+
+	c.cache.Set(order.OrderUID, order)
+	c.server.Close()
+
+	if c.cache.Get(order.OrderUID) == nil {
+		t.Fatal("Get failed while the local tier holds the order")
+	}
+	if c.cache.Get("unknown") != nil {
+		t.Fatal("Get of an unknown order returned an order")
+	}
+}
+
+func TestDeleteDropsBothTiers(t *testing.T) {
+	c := newTestCache(t, &storage{})
+	order := generateOrders(1)[0]
+
+	c.cache.Set(order.OrderUID, order)
+	if !c.cache.Delete(order.OrderUID) {
+		t.Fatal("Delete of a cached order reported false")
+	}
+	if c.local.Peek(order.OrderUID) != nil || c.remote.Peek(order.OrderUID) != nil {
+		t.Fatal("order is still cached after Delete")
+	}
+}
+
+func TestRunWarmsUpFromStorageOnce(t *testing.T) {
+	initializer := &storage{orders: generateOrders(5)}
+	c := newTestCache(t, initializer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() { runDone <- c.cache.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !c.cache.HealthCheck(ctx).Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("cache did not become ready")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-runDone; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if loads := initializer.loads.Load(); loads != 1 {
+		t.Fatalf("storage loaded %d times, want 1", loads)
+	}
+	for _, order := range initializer.orders {
+		if c.local.Peek(order.OrderUID) == nil {
+			t.Fatalf("order %s was not seeded into the local tier", order.OrderUID)
+		}
+	}
+}
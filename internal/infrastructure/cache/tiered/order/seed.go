@@ -0,0 +1,39 @@
+package order
+
+import (
+	"context"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	redisCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/redis/order"
+)
+
+// remoteSeed is the initializer of the local tier. Warm-ups read the orders Redis
+// already holds instead of querying storage a second time, and read counters go
+// to storage as usual.
+type remoteSeed struct {
+	remote      *redisCache.Cache
+	initializer ports.CacheInitializer
+}
+
+// LocalInitializer returns the initializer to build the local tier with.
+func LocalInitializer(remote *redisCache.Cache, initializer ports.CacheInitializer) ports.CacheInitializer {
+	return &remoteSeed{
+		remote:      remote,
+		initializer: initializer,
+	}
+}
+
+func (s *remoteSeed) GetOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
+	return s.remote.CachedOrders(ctx, limit)
+}
+
+// GetMostReadOrdersForCache returns what Redis holds as well: Redis was warmed up
+// with the configured strategy already.
+func (s *remoteSeed) GetMostReadOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
+	return s.remote.CachedOrders(ctx, limit)
+}
+
+func (s *remoteSeed) RecordOrderReads(ctx context.Context, reads map[string]int64) error {
+	return s.initializer.RecordOrderReads(ctx, reads)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Cache configures the order cache.
+// Backend is memory, redis, or tiered (an in-process cache in front of Redis).
+// StaleWhileRevalidate is how long an expired order may still be served while it is
+// reloaded in the background, and NegativeTTL is how long a UID that was not found is
+// remembered as missing; zero disables either. Shards is the number of independently
+// locked segments of the in-process cache, rounded up to a power of two and lowered
+// when max_entries is smaller, so each shard holds at least one entry.
+type Cache struct {
+	Backend              string        `yaml:"backend" env:"CACHE_BACKEND" env-default:"memory"`
+	TTL                  time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"5m"`
+	CleanupInterval      time.Duration `yaml:"cleanup_interval" env:"CACHE_CLEANUP_INTERVAL" env-default:"150s"`
+	StaleWhileRevalidate time.Duration `yaml:"stale_while_revalidate" env:"CACHE_STALE_WHILE_REVALIDATE" env-default:"0s"`
+	NegativeTTL          time.Duration `yaml:"negative_ttl" env:"CACHE_NEGATIVE_TTL" env-default:"30s"`
+	Shards               int           `yaml:"shards" env:"CACHE_SHARDS" env-default:"16"`
+	WarmUp               CacheWarmUp   `yaml:"warm_up"`
+	Eviction             CacheEviction `yaml:"eviction"`
+	Redis                CacheRedis    `yaml:"redis"`
+	Snapshot             CacheSnapshot `yaml:"snapshot"`
+}
+
+// CacheSnapshot persists the in-process cache to a local file,
+// loaded on start before the warm-up from storage.
+type CacheSnapshot struct {
+	Enabled  bool          `yaml:"enabled" env:"CACHE_SNAPSHOT_ENABLED"`
+	Path     string        `yaml:"path" env:"CACHE_SNAPSHOT_PATH" env-default:"./data/cache.snapshot"`
+	Interval time.Duration `yaml:"interval" env:"CACHE_SNAPSHOT_INTERVAL" env-default:"5m"`
+}
+
+type CacheWarmUp struct {
+	Count    int    `yaml:"count" env:"CACHE_WARM_UP_COUNT" env-default:"100"`
+	Strategy string `yaml:"strategy" env:"CACHE_WARM_UP_STRATEGY" env-default:"latest"`
+}
+
+type CacheEviction struct {
+	Policy     string `yaml:"policy" env:"CACHE_EVICTION_POLICY" env-default:"w-tinylfu"`
+	MaxEntries int    `yaml:"max_entries" env:"CACHE_MAX_ENTRIES" env-default:"10000"`
+	MaxBytes   int64  `yaml:"max_bytes" env:"CACHE_MAX_BYTES" env-default:"67108864"`
+}
+
+type CacheRedis struct {
+	Address          string        `yaml:"address" env:"CACHE_REDIS_ADDRESS" env-default:"redis:6379"`
+	Password         string        `yaml:"password" env:"CACHE_REDIS_PASSWORD"`
+	DB               int           `yaml:"db" env:"CACHE_REDIS_DB" env-default:"0"`
+	KeyPrefix        string        `yaml:"key_prefix" env:"CACHE_REDIS_KEY_PREFIX" env-default:"orders:"`
+	PoolSize         int           `yaml:"pool_size" env:"CACHE_REDIS_POOL_SIZE" env-default:"10"`
+	DialTimeout      time.Duration `yaml:"dial_timeout" env:"CACHE_REDIS_DIAL_TIMEOUT" env-default:"5s"`
+	OperationTimeout time.Duration `yaml:"operation_timeout" env:"CACHE_REDIS_OPERATION_TIMEOUT" env-default:"200ms"`
+}
+
+func (c *Cache) Validate() error {
+	var errs []error
+
+	switch c.Backend {
+	case "memory":
+	case "redis", "tiered":
+		if c.Redis.Address == "" {
+			errs = append(errs, fmt.Errorf("redis.address is required for the %s backend", c.Backend))
+		}
+		if c.Redis.OperationTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("redis.operation_timeout must be positive, got %s", c.Redis.OperationTimeout))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("backend must be memory, redis or tiered, got %q", c.Backend))
+	}
+
+	if c.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("ttl must be positive, got %s", c.TTL))
+	}
+	if c.CleanupInterval <= 0 {
+		errs = append(errs, fmt.Errorf("cleanup_interval must be positive, got %s", c.CleanupInterval))
+	}
+	if c.NegativeTTL < 0 {
+		errs = append(errs, fmt.Errorf("negative_ttl must not be negative, got %s", c.NegativeTTL))
+	}
+	if c.Shards < 1 || c.Shards > 1024 {
+		errs = append(errs, fmt.Errorf("shards must be between 1 and 1024, got %d", c.Shards))
+	}
+	if c.StaleWhileRevalidate < 0 {
+		errs = append(errs, fmt.Errorf("stale_while_revalidate must not be negative, got %s", c.StaleWhileRevalidate))
+	}
+
+	if c.WarmUp.Count < 0 {
+		errs = append(errs, fmt.Errorf("warm_up.count must not be negative, got %d", c.WarmUp.Count))
+	}
+	switch c.WarmUp.Strategy {
+	case "latest", "most_read":
+	default:
+		errs = append(errs, fmt.Errorf("warm_up.strategy must be latest or most_read, got %q", c.WarmUp.Strategy))
+	}
+
+	switch c.Eviction.Policy {
+	case "lru", "lfu", "w-tinylfu":
+	default:
+		errs = append(errs, fmt.Errorf("eviction.policy must be lru, lfu or w-tinylfu, got %q", c.Eviction.Policy))
+	}
+	if c.Eviction.MaxEntries < 0 {
+		errs = append(errs, fmt.Errorf("eviction.max_entries must not be negative, got %d", c.Eviction.MaxEntries))
+	}
+	if c.Eviction.MaxBytes < 0 {
+		errs = append(errs, fmt.Errorf("eviction.max_bytes must not be negative, got %d", c.Eviction.MaxBytes))
+	}
+	if c.Eviction.MaxEntries > 0 && c.WarmUp.Count > c.Eviction.MaxEntries {
+		errs = append(errs, fmt.Errorf("warm_up.count %d exceeds eviction.max_entries %d",
+			c.WarmUp.Count, c.Eviction.MaxEntries))
+	}
+
+	if c.Snapshot.Enabled {
+		if c.Snapshot.Path == "" {
+			errs = append(errs, errors.New("snapshot.path is required when snapshots are enabled"))
+		}
+		if c.Snapshot.Interval <= 0 {
+			errs = append(errs, fmt.Errorf("snapshot.interval must be positive, got %s", c.Snapshot.Interval))
+		}
+	}
+
+	return errors.Join(errs...)
+}
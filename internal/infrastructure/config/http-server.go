@@ -11,4 +11,5 @@ type HTTPServer struct {
 	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
 	CORS         bool          `yaml:"cors" env:"HTTP_CORS"`
 	AllowOrigins []string      `yaml:"allow_origins" env:"HTTP_ALLOWED_ORIGINS"`
+	AdminToken   string        `yaml:"admin_token" env:"HTTP_ADMIN_TOKEN"`
 }
@@ -0,0 +1,5 @@
+package config
+
+type Ingestion struct {
+	ConflictPolicy string `yaml:"conflict_policy" env:"INGESTION_CONFLICT_POLICY" env-default:"reject"`
+}
@@ -1,13 +1,53 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"os"
+	"time"
+)
 
 type Kafka struct {
-	Address          string        `yaml:"address" env:"KAFKA_ADDRESS"`
-	OrdersTopic      string        `yaml:"orders_topic" env:"KAFKA_ORDERS_TOPIC"`
-	SaverGroup       string        `yaml:"saver_group" env:"KAFKA_SAVER_GROUP"`
-	BroadcasterGroup string        `yaml:"broadcaster_group" env:"KAFKA_BROADCASTER_GROUP"`
-	CreateTopic      bool          `yaml:"create_topic" env:"KAFKA_CREATE_TOPIC"`
-	SessionTimeout   time.Duration `yaml:"session_timeout" env:"KAFKA_SESSION_TIMEOUT" env-default:"30s"`
-	MaxPollInterval  time.Duration `yaml:"max_poll_interval" env:"KAFKA_MAX_POLL_INTERVAL" env-default:"5m"`
+	Address           string        `yaml:"address" env:"KAFKA_ADDRESS"`
+	OrdersTopic       string        `yaml:"orders_topic" env:"KAFKA_ORDERS_TOPIC"`
+	DLQTopic          string        `yaml:"dlq_topic" env:"KAFKA_DLQ_TOPIC" env-default:"orders-dlq"`
+	OrderEventsTopic  string        `yaml:"order_events_topic" env:"KAFKA_ORDER_EVENTS_TOPIC" env-default:"order-events"`
+	InvalidationTopic string        `yaml:"invalidation_topic" env:"KAFKA_INVALIDATION_TOPIC" env-default:"order-invalidations"`
+	SaverGroup        string        `yaml:"saver_group" env:"KAFKA_SAVER_GROUP"`
+	BroadcasterGroup  string        `yaml:"broadcaster_group" env:"KAFKA_BROADCASTER_GROUP" env-default:"broadcaster-group"`
+	ReplicaID         string        `yaml:"replica_id" env:"KAFKA_REPLICA_ID"`
+	CreateTopic       bool          `yaml:"create_topic" env:"KAFKA_CREATE_TOPIC"`
+	SessionTimeout    time.Duration `yaml:"session_timeout" env:"KAFKA_SESSION_TIMEOUT" env-default:"30s"`
+	MaxPollInterval   time.Duration `yaml:"max_poll_interval" env:"KAFKA_MAX_POLL_INTERVAL" env-default:"5m"`
+	MaxInFlight       int           `yaml:"max_in_flight" env:"KAFKA_MAX_IN_FLIGHT" env-default:"64"`
+	WriteBatchTimeout time.Duration `yaml:"write_batch_timeout" env:"KAFKA_WRITE_BATCH_TIMEOUT" env-default:"10ms"`
+	Retry             ConsumerRetry `yaml:"retry"`
+	Batch             ConsumerBatch `yaml:"batch"`
+}
+
+type ConsumerRetry struct {
+	MaxAttempts    int           `yaml:"max_attempts" env:"KAFKA_RETRY_MAX_ATTEMPTS" env-default:"5"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"KAFKA_RETRY_INITIAL_BACKOFF" env-default:"200ms"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"KAFKA_RETRY_MAX_BACKOFF" env-default:"10s"`
+	Multiplier     float64       `yaml:"multiplier" env:"KAFKA_RETRY_MULTIPLIER" env-default:"2"`
+	PauseInterval  time.Duration `yaml:"pause_interval" env:"KAFKA_RETRY_PAUSE_INTERVAL" env-default:"30s"`
+}
+
+type ConsumerBatch struct {
+	Enabled bool          `yaml:"enabled" env:"KAFKA_BATCH_ENABLED"`
+	Size    int           `yaml:"size" env:"KAFKA_BATCH_SIZE" env-default:"50"`
+	Timeout time.Duration `yaml:"timeout" env:"KAFKA_BATCH_TIMEOUT" env-default:"200ms"`
+}
+
+func (k *Kafka) setDefaults() error {
+	if k.ReplicaID != "" {
+		return nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("replica_id is not set and the hostname is unavailable: %w", err)
+	}
+	k.ReplicaID = hostname
+
+	return nil
 }
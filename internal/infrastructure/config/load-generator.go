@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LoadGenerator configures the mock producer that writes generated orders into the orders
+// topic for load tests; it is off by default. Rate is the sustained rate in orders per second
+// and Burst the number of orders that may go out at once above it. The run stops after Count
+// orders or Duration, whichever comes first; zero means no limit.
+type LoadGenerator struct {
+	Enabled   bool          `yaml:"enabled" env:"LOADGEN_ENABLED"`
+	Rate      float64       `yaml:"rate" env:"LOADGEN_RATE" env-default:"1"`
+	Burst     int           `yaml:"burst" env:"LOADGEN_BURST" env-default:"1"`
+	Count     int           `yaml:"count" env:"LOADGEN_COUNT"`
+	Duration  time.Duration `yaml:"duration" env:"LOADGEN_DURATION"`
+	Producers int           `yaml:"producers" env:"LOADGEN_PRODUCERS" env-default:"1"`
+	Profile   OrderProfile  `yaml:"profile"`
+}
+
+// OrderProfile shapes the generated orders. Currencies and Locales map a value to its
+// relative weight; when empty, every order is in USD with the en locale.
+type OrderProfile struct {
+	MinItems   int            `yaml:"min_items" env:"LOADGEN_MIN_ITEMS" env-default:"1"`
+	MaxItems   int            `yaml:"max_items" env:"LOADGEN_MAX_ITEMS" env-default:"5"`
+	Currencies map[string]int `yaml:"currencies"`
+	Locales    map[string]int `yaml:"locales"`
+}
+
+func (l *LoadGenerator) Validate() error {
+	if !l.Enabled {
+		return nil
+	}
+
+	var errs []error
+
+	if l.Rate <= 0 {
+		errs = append(errs, fmt.Errorf("rate must be positive, got %v", l.Rate))
+	}
+	if l.Burst < 1 {
+		errs = append(errs, fmt.Errorf("burst must be at least 1, got %d", l.Burst))
+	}
+	if l.Count < 0 || l.Duration < 0 {
+		errs = append(errs, errors.New("count and duration must not be negative"))
+	}
+	if l.Producers < 1 {
+		errs = append(errs, fmt.Errorf("producers must be at least 1, got %d", l.Producers))
+	}
+	if l.Profile.MinItems < 1 || l.Profile.MaxItems < l.Profile.MinItems {
+		errs = append(errs, fmt.Errorf(
+			"profile items must satisfy 1 <= min_items <= max_items, got %d and %d",
+			l.Profile.MinItems, l.Profile.MaxItems,
+		))
+	}
+	for name, weights := range map[string]map[string]int{
+		"currencies": l.Profile.Currencies,
+		"locales":    l.Profile.Locales,
+	} {
+		for value, weight := range weights {
+			if weight < 1 {
+				errs = append(errs, fmt.Errorf("profile %s: weight of %q must be positive, got %d", name, value, weight))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Logging configures the service logger. Level is debug, info, warn or error and can be
+// changed at runtime through the admin API; Format is json or text.
+type Logging struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+	Format string `yaml:"format" env:"LOG_FORMAT" env-default:"json"`
+}
+
+func (l *Logging) Validate() error {
+	var errs []error
+
+	switch l.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("level must be debug, info, warn or error, got %q", l.Level))
+	}
+
+	switch l.Format {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("format must be json or text, got %q", l.Format))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Outbox configures the relay. ClaimLease is how long claimed events stay with one relay
+// before another may publish them again; it must outlast a Kafka write.
+type Outbox struct {
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+	BatchSize    int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	ClaimLease   time.Duration `yaml:"claim_lease" env:"OUTBOX_CLAIM_LEASE" env-default:"30s"`
+}
+
+func (o *Outbox) Validate() error {
+	var errs []error
+
+	if o.PollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("poll_interval must be positive, got %s", o.PollInterval))
+	}
+	if o.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("batch_size must be positive, got %d", o.BatchSize))
+	}
+	if o.ClaimLease <= 0 {
+		errs = append(errs, fmt.Errorf("claim_lease must be positive, got %s", o.ClaimLease))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Supervisor configures how app components are restarted when they stop. Restart is the
+// default policy: never, on-failure or always. Under on-failure a component returning
+// before the app stops has failed even without an error; always restarts such a clean
+// exit without charging it to the budget. MaxRestarts is the budget of failure restarts
+// in a row; running for ResetAfter without failing refills it. Only Critical components
+// bring the process down once they give up and gate readiness; by default those are
+// storage and the HTTP server. Readiness lists components that gate readiness without
+// being critical, by default the cache, so the app is not ready before the warm-up has
+// finished.
+type Supervisor struct {
+	Restart     string                         `yaml:"restart" env:"SUPERVISOR_RESTART" env-default:"never"`
+	MaxRestarts int                            `yaml:"max_restarts" env:"SUPERVISOR_MAX_RESTARTS" env-default:"5"`
+	Backoff     time.Duration                  `yaml:"backoff" env:"SUPERVISOR_BACKOFF" env-default:"1s"`
+	MaxBackoff  time.Duration                  `yaml:"max_backoff" env:"SUPERVISOR_MAX_BACKOFF" env-default:"30s"`
+	ResetAfter  time.Duration                  `yaml:"reset_after" env:"SUPERVISOR_RESET_AFTER" env-default:"5m"`
+	Critical    []string                       `yaml:"critical" env:"SUPERVISOR_CRITICAL" env-default:"postgres,http"`
+	Readiness   []string                       `yaml:"readiness" env:"SUPERVISOR_READINESS" env-default:"cache"`
+	Components  map[string]SupervisedComponent `yaml:"components"`
+}
+
+// SupervisedComponent overrides the default policy and budget for one component.
+// A nil MaxRestarts keeps the default, while 0 means the component is not restarted.
+type SupervisedComponent struct {
+	Restart     string `yaml:"restart"`
+	MaxRestarts *int   `yaml:"max_restarts"`
+}
+
+func (s *Supervisor) Validate() error {
+	var errs []error
+
+	if err := validateRestartPolicy(s.Restart); err != nil {
+		errs = append(errs, err)
+	}
+	if s.MaxRestarts < 0 {
+		errs = append(errs, fmt.Errorf("max_restarts must not be negative, got %d", s.MaxRestarts))
+	}
+	if s.Backoff <= 0 || s.MaxBackoff < s.Backoff {
+		errs = append(errs, fmt.Errorf("backoff must be positive and not above max_backoff, got %s and %s", s.Backoff, s.MaxBackoff))
+	}
+	if s.ResetAfter <= 0 {
+		errs = append(errs, fmt.Errorf("reset_after must be positive, got %s", s.ResetAfter))
+	}
+
+	for name, component := range s.Components {
+		if component.Restart != "" {
+			if err := validateRestartPolicy(component.Restart); err != nil {
+				errs = append(errs, fmt.Errorf("components.%s: %w", name, err))
+			}
+		}
+		if component.MaxRestarts != nil && *component.MaxRestarts < 0 {
+			errs = append(errs, fmt.Errorf("components.%s: max_restarts must not be negative, got %d", name, *component.MaxRestarts))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateRestartPolicy(policy string) error {
+	switch policy {
+	case "never", "on-failure", "always":
+		return nil
+	default:
+		return fmt.Errorf("restart must be never, on-failure or always, got %q", policy)
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tracing configures OpenTelemetry tracing. Exporter is otlp, which sends spans over
+// OTLP/HTTP to Endpoint, or stdout, which writes them as JSON to File, or to standard
+// output when File is empty. SampleRatio is the share of new traces that are recorded.
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled" env:"TRACING_ENABLED"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME" env-default:"orders-api"`
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"otlp"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT" env-default:"localhost:4318"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE" env-default:"true"`
+	File        string  `yaml:"file" env:"TRACING_FILE"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+func (t *Tracing) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+
+	var errs []error
+
+	switch t.Exporter {
+	case "otlp":
+		if t.Endpoint == "" {
+			errs = append(errs, errors.New("endpoint is required for the otlp exporter"))
+		}
+	case "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("exporter must be otlp or stdout, got %q", t.Exporter))
+	}
+
+	if t.ServiceName == "" {
+		errs = append(errs, errors.New("service_name is required"))
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("sample_ratio must be between 0 and 1, got %g", t.SampleRatio))
+	}
+
+	return errors.Join(errs...)
+}
@@ -9,9 +9,16 @@ import (
 const basicConfigPath = "./configs/api/prod.yaml"
 
 type Config struct {
-	Server        HTTPServer `yaml:"server"`
-	MessageBroker Kafka      `yaml:"message_broker"`
-	Storage       Postgres   `yaml:"storage"`
+	Server        HTTPServer    `yaml:"server"`
+	MessageBroker Kafka         `yaml:"message_broker"`
+	Storage       Postgres      `yaml:"storage"`
+	Ingestion     Ingestion     `yaml:"ingestion"`
+	Outbox        Outbox        `yaml:"outbox"`
+	Cache         Cache         `yaml:"cache"`
+	Tracing       Tracing       `yaml:"tracing"`
+	Logging       Logging       `yaml:"logging"`
+	Supervisor    Supervisor    `yaml:"supervisor"`
+	LoadGenerator LoadGenerator `yaml:"load_generator"`
 }
 
 func NewConfig() *Config {
@@ -26,5 +33,33 @@ func NewConfig() *Config {
 		panic("failed to read config: " + err.Error())
 	}
 
+	if err := cfg.MessageBroker.setDefaults(); err != nil {
+		panic("invalid message broker config: " + err.Error())
+	}
+
+	if err := cfg.Outbox.Validate(); err != nil {
+		panic("invalid outbox config: " + err.Error())
+	}
+
+	if err := cfg.Cache.Validate(); err != nil {
+		panic("invalid cache config: " + err.Error())
+	}
+
+	if err := cfg.Tracing.Validate(); err != nil {
+		panic("invalid tracing config: " + err.Error())
+	}
+
+	if err := cfg.Logging.Validate(); err != nil {
+		panic("invalid logging config: " + err.Error())
+	}
+
+	if err := cfg.Supervisor.Validate(); err != nil {
+		panic("invalid supervisor config: " + err.Error())
+	}
+
+	if err := cfg.LoadGenerator.Validate(); err != nil {
+		panic("invalid load generator config: " + err.Error())
+	}
+
 	return &cfg
 }
@@ -0,0 +1,211 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ErrGenerationEnded is returned by PartitionReader.CommitMessages once the partition
+// has been revoked by a rebalance. The uncommitted messages are re-delivered to the
+// new owner of the partition.
+var ErrGenerationEnded = errors.New("consumer group generation has ended")
+
+// GroupConsumer joins a consumer group and hands every assigned partition
+// to its own handler, so partitions are consumed independently.
+type GroupConsumer struct {
+	log     appPorts.Logger
+	brokers []string
+	topic   string
+	group   *kafka.ConsumerGroup
+	joined  atomic.Bool
+}
+
+type PartitionHandler func(ctx context.Context, partition *PartitionReader)
+
+func NewGroupConsumer(log appPorts.Logger, cfg *config.Kafka, group, topic string) (*GroupConsumer, error) {
+	return newGroupConsumer(log, cfg, group, topic, kafka.FirstOffset)
+}
+
+// NewBroadcastConsumer joins a group of its own, named after the broadcaster group
+// and the replica, so every replica receives every message of the topic. A replica
+// seen for the first time starts at the end of the topic instead of replaying it.
+func NewBroadcastConsumer(log appPorts.Logger, cfg *config.Kafka, topic string) (*GroupConsumer, error) {
+	return newGroupConsumer(log, cfg, cfg.BroadcasterGroup+"-"+cfg.ReplicaID, topic, kafka.LastOffset)
+}
+
+func newGroupConsumer(
+	log appPorts.Logger,
+	cfg *config.Kafka,
+	group, topic string,
+	startOffset int64,
+) (*GroupConsumer, error) {
+	const op = "kafka.newGroupConsumer"
+
+	consumerGroup, err := kafka.NewConsumerGroup(kafka.ConsumerGroupConfig{
+		ID:             group,
+		Brokers:        []string{cfg.Address},
+		Topics:         []string{topic},
+		SessionTimeout: cfg.SessionTimeout,
+		StartOffset:    startOffset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: group %s: %w", op, group, err)
+	}
+
+	return &GroupConsumer{
+		log:     log,
+		brokers: []string{cfg.Address},
+		topic:   topic,
+		group:   consumerGroup,
+	}, nil
+}
+
+func (c *GroupConsumer) GetTopic() string {
+	return c.topic
+}
+
+// Consume runs handle for every partition of every generation until ctx is done.
+// It returns only after all handlers of the current generation have exited.
+func (c *GroupConsumer) Consume(ctx context.Context, handle PartitionHandler) error {
+	const op = "kafka.GroupConsumer.Consume"
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		gen, err := c.group.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, kafka.ErrGroupClosed) {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		c.joined.Store(true)
+		// The generation ends on a rebalance or when the group is left; the consumer is
+		// ready again only once Next joins the next generation.
+		gen.Start(func(genCtx context.Context) {
+			<-genCtx.Done()
+			c.joined.Store(false)
+		})
+
+		assignments := gen.Assignments[c.topic]
+		c.log.Info("Kafka consumer group generation started",
+			"op", op,
+			"generation", gen.ID,
+			"topic", c.topic,
+			"partitions", len(assignments),
+		)
+
+		for _, assignment := range assignments {
+			wg.Add(1)
+			gen.Start(func(genCtx context.Context) {
+				defer wg.Done()
+
+				partitionCtx, cancel := context.WithCancel(genCtx)
+				defer cancel()
+				stop := context.AfterFunc(ctx, cancel)
+				defer stop()
+
+				partition := c.newPartitionReader(genCtx, gen, assignment)
+				defer func() { _ = partition.reader.Close() }()
+
+				handle(partitionCtx, partition)
+			})
+		}
+	}
+}
+
+func (c *GroupConsumer) newPartitionReader(
+	genCtx context.Context,
+	gen *kafka.Generation,
+	assignment kafka.PartitionAssignment,
+) *PartitionReader {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   c.brokers,
+		Topic:     c.topic,
+		Partition: assignment.ID,
+	})
+	if err := reader.SetOffset(assignment.Offset); err != nil {
+		c.log.Error("Failed to set partition offset",
+			"partition", assignment.ID,
+			"offset", assignment.Offset,
+			"error", err.Error(),
+		)
+	}
+
+	return &PartitionReader{
+		reader: reader,
+		gen:    gen,
+		genCtx: genCtx,
+		topic:  c.topic,
+		id:     assignment.ID,
+	}
+}
+
+func (c *GroupConsumer) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// HealthCheck reports the consumer ready once it has joined its group.
+func (c *GroupConsumer) HealthCheck(_ context.Context) appPorts.Health {
+	if !c.joined.Load() {
+		return appPorts.NotReady("joining consumer group")
+	}
+	return appPorts.Up()
+}
+
+func (c *GroupConsumer) Shutdown(_ context.Context) error {
+	_ = c.group.Close()
+	return nil
+}
+
+// PartitionReader reads a single assigned partition and commits its offsets
+// through the generation that owns the assignment.
+type PartitionReader struct {
+	reader *kafka.Reader
+	gen    *kafka.Generation
+	genCtx context.Context
+	topic  string
+	id     int
+}
+
+func (p *PartitionReader) ID() int {
+	return p.id
+}
+
+// FetchMessage returns ErrGenerationEnded instead of the fetch error once the
+// partition has been revoked by a rebalance.
+func (p *PartitionReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	message, err := p.reader.FetchMessage(ctx)
+	if err != nil && p.genCtx.Err() != nil {
+		return message, ErrGenerationEnded
+	}
+	return message, err
+}
+
+// CommitMessages commits through the owning generation. Work drained after a rebalance
+// is not committed: the generation is already over, so ErrGenerationEnded is returned
+// and the messages are left for the partition's next owner.
+func (p *PartitionReader) CommitMessages(messages ...kafka.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	if p.genCtx.Err() != nil {
+		return ErrGenerationEnded
+	}
+
+	last := messages[len(messages)-1]
+	return p.gen.CommitOffsets(map[string]map[int]int64{
+		p.topic: {p.id: last.Offset + 1},
+	})
+}
@@ -0,0 +1,19 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageID identifies a message as topic/partition/offset, the same way
+// in logs of every service that reads it.
+func MessageID(message kafka.Message) string {
+	return fmt.Sprintf("%s/%d/%d", message.Topic, message.Partition, message.Offset)
+}
+
+// BatchID identifies consecutive messages of one partition as topic/partition/first-last.
+func BatchID(messages []kafka.Message) string {
+	first, last := messages[0], messages[len(messages)-1]
+	return fmt.Sprintf("%s/%d/%d-%d", first.Topic, first.Partition, first.Offset, last.Offset)
+}
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
@@ -14,23 +15,34 @@ import (
 type Writer struct {
 	log ports.Logger
 	*kafka.Writer
-	address       string
-	topic         string
-	isCreateTopic bool
+	address           string
+	topic             string
+	dlqTopic          string
+	eventsTopic       string
+	invalidationTopic string
+	isCreateTopic     bool
+	topicsReady       atomic.Bool
 }
 
 func NewWriter(log ports.Logger, cfg *config.Kafka) *Writer {
 	writer := &kafka.Writer{
-		Addr:     kafka.TCP([]string{cfg.Address}...),
-		Balancer: &kafka.LeastBytes{},
+		Addr:         kafka.TCP([]string{cfg.Address}...),
+		RequiredAcks: kafka.RequireAll,
+		// Messages with the same key go to one partition, so they are consumed in order.
+		Balancer: &kafka.Hash{},
+		// Writes are synchronous, so every call waits up to this long for its batch to fill.
+		BatchTimeout: cfg.WriteBatchTimeout,
 	}
 
 	return &Writer{
-		log:           log,
-		address:       cfg.Address,
-		topic:         cfg.OrdersTopic,
-		Writer:        writer,
-		isCreateTopic: cfg.CreateTopic,
+		log:               log,
+		address:           cfg.Address,
+		topic:             cfg.OrdersTopic,
+		dlqTopic:          cfg.DLQTopic,
+		eventsTopic:       cfg.OrderEventsTopic,
+		invalidationTopic: cfg.InvalidationTopic,
+		Writer:            writer,
+		isCreateTopic:     cfg.CreateTopic,
 	}
 }
 
@@ -38,6 +50,18 @@ func (w *Writer) GetTopic() string {
 	return w.topic
 }
 
+func (w *Writer) GetDLQTopic() string {
+	return w.dlqTopic
+}
+
+func (w *Writer) GetOrderEventsTopic() string {
+	return w.eventsTopic
+}
+
+func (w *Writer) GetInvalidationTopic() string {
+	return w.invalidationTopic
+}
+
 const (
 	partitions        = 3
 	replicationFactor = 1
@@ -50,11 +74,19 @@ func (w *Writer) createTopic() error {
 	}
 	defer func() { _ = conn.Close() }()
 
-	if err = conn.CreateTopics(kafka.TopicConfig{
-		Topic:             w.topic,
-		NumPartitions:     partitions,
-		ReplicationFactor: replicationFactor,
-	}); err != nil {
+	topics := make([]kafka.TopicConfig, 0, 4)
+	for _, topic := range []string{w.topic, w.dlqTopic, w.eventsTopic, w.invalidationTopic} {
+		if topic == "" {
+			continue
+		}
+		topics = append(topics, kafka.TopicConfig{
+			Topic:             topic,
+			NumPartitions:     partitions,
+			ReplicationFactor: replicationFactor,
+		})
+	}
+
+	if err = conn.CreateTopics(topics...); err != nil {
 		return err
 	}
 
@@ -69,6 +101,7 @@ func (w *Writer) Run(ctx context.Context) error {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 	}
+	w.topicsReady.Store(true)
 
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
@@ -83,6 +116,14 @@ func (w *Writer) Run(ctx context.Context) error {
 	}
 }
 
+// HealthCheck reports the writer ready once its topics are created.
+func (w *Writer) HealthCheck(_ context.Context) ports.Health {
+	if !w.topicsReady.Load() {
+		return ports.NotReady("creating topics")
+	}
+	return ports.Up()
+}
+
 func (w *Writer) Shutdown(_ context.Context) error {
 	_ = w.Close()
 	return nil
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+)
+
+// Logger is the service logger. Every record is enriched with the correlation ID,
+// fields and trace ID carried by the context it is logged with, and the level
+// can be changed while the service is running.
+type Logger struct {
+	*slog.Logger
+	level *slog.LevelVar
+}
+
+func New(cfg *config.Logging) *Logger {
+	level := new(slog.LevelVar)
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		panic(fmt.Errorf("logger.New: %w", err))
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch cfg.Format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	default:
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+
+	l := &Logger{
+		Logger: slog.New(&contextHandler{Handler: handler}),
+		level:  level,
+	}
+	// Libraries logging through the standard logger end up in the same output.
+	slog.SetDefault(l.Logger)
+
+	return l
+}
+
+// Level reports the current minimum level as debug, info, warn or error.
+func (l *Logger) Level() string {
+	return strings.ToLower(l.level.Level().String())
+}
+
+func (l *Logger) SetLevel(level string) error {
+	const op = "logger.Logger.SetLevel"
+
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	previous := l.Level()
+	l.level.Set(parsed)
+	l.Info("Log level changed", "op", op, "from", previous, "to", l.Level())
+
+	return nil
+}
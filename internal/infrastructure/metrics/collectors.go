@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterCache exports the cache counters, read from stats on every scrape.
+func (m *Metrics) RegisterCache(stats func() model.CacheStats) {
+	m.registry.MustRegister(&cacheCollector{stats: stats})
+}
+
+// RegisterPool exports the connection pool statistics, read on every scrape.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(&poolCollector{pool: pool})
+}
+
+var (
+	cacheHits = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hits_total"),
+		"Cache lookups that found a live order.", nil, nil,
+	)
+	cacheMisses = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "misses_total"),
+		"Cache lookups that found nothing or an expired order.", nil, nil,
+	)
+	cacheEvictions = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+		"Orders evicted to stay within the cache bounds.", nil, nil,
+	)
+	cacheEntries = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "entries"),
+		"Orders currently cached.", nil, nil,
+	)
+	cacheBytes = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "bytes"),
+		"Approximate memory held by cached orders.", nil, nil,
+	)
+	cacheTierHits = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "tier_hits_total"),
+		"Lookups that found a live order, per tier of a layered cache.", []string{"tier"}, nil,
+	)
+	cacheTierMisses = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "tier_misses_total"),
+		"Lookups that found nothing or an expired order, per tier of a layered cache.", []string{"tier"}, nil,
+	)
+)
+
+type cacheCollector struct {
+	stats func() model.CacheStats
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHits
+	ch <- cacheMisses
+	ch <- cacheEvictions
+	ch <- cacheEntries
+	ch <- cacheBytes
+	ch <- cacheTierHits
+	ch <- cacheTierMisses
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+
+	ch <- prometheus.MustNewConstMetric(cacheHits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(cacheMisses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(cacheEvictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(cacheEntries, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(cacheBytes, prometheus.GaugeValue, float64(stats.Bytes))
+
+	for tier, tierStats := range stats.Tiers {
+		ch <- prometheus.MustNewConstMetric(cacheTierHits, prometheus.CounterValue, float64(tierStats.Hits), tier)
+		ch <- prometheus.MustNewConstMetric(cacheTierMisses, prometheus.CounterValue, float64(tierStats.Misses), tier)
+	}
+}
+
+var (
+	poolAcquired = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquired_connections"),
+		"Connections currently in use.", nil, nil,
+	)
+	poolIdle = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "idle_connections"),
+		"Connections currently idle.", nil, nil,
+	)
+	poolTotal = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "total_connections"),
+		"Connections currently open.", nil, nil,
+	)
+	poolMax = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "max_connections"),
+		"Maximum size of the pool.", nil, nil,
+	)
+	poolAcquires = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquires_total"),
+		"Successful connection acquisitions.", nil, nil,
+	)
+	poolEmptyAcquires = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "empty_acquires_total"),
+		"Acquisitions that had to wait for a connection.", nil, nil,
+	)
+	poolAcquireWait = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "db_pool", "acquire_wait_seconds_total"),
+		"Total time spent waiting for a connection.", nil, nil,
+	)
+)
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquired
+	ch <- poolIdle
+	ch <- poolTotal
+	ch <- poolMax
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolAcquireWait
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(poolAcquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotal, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMax, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireWait, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
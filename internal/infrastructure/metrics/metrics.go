@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "orders"
+
+// Metrics owns the Prometheus registry of the service and the collectors
+// that components update as they work.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	messagesConsumed  *prometheus.CounterVec
+	messagesCommitted *prometheus.CounterVec
+	messagesFailed    *prometheus.CounterVec
+	consumerLag       *prometheus.GaugeVec
+	httpDuration      *prometheus.HistogramVec
+	dbDuration        *prometheus.HistogramVec
+	componentFailures *prometheus.CounterVec
+	componentRestarts *prometheus.CounterVec
+	componentUp       *prometheus.GaugeVec
+}
+
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		registry: registry,
+		messagesConsumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "messages_consumed_total",
+			Help:      "Messages fetched from Kafka.",
+		}, []string{"topic"}),
+		messagesCommitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "messages_committed_total",
+			Help:      "Messages whose offsets were committed.",
+		}, []string{"topic"}),
+		messagesFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "messages_failed_total",
+			Help:      "Messages that could not be processed, by the stage that failed.",
+		}, []string{"topic", "stage"}),
+		consumerLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "consumer_lag",
+			Help:      "Messages left in the partition after the last fetched one.",
+		}, []string{"topic", "partition"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of order storage operations.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "result"}),
+		componentFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "app",
+			Name:      "component_failures_total",
+			Help:      "Times an app component stopped with an error.",
+		}, []string{"component"}),
+		componentRestarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "app",
+			Name:      "component_restarts_total",
+			Help:      "Times the supervisor restarted an app component.",
+		}, []string{"component"}),
+		componentUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "app",
+			Name:      "component_up",
+			Help:      "Whether an app component is running (1) or not (0).",
+		}, []string{"component"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.messagesConsumed,
+		m.messagesCommitted,
+		m.messagesFailed,
+		m.consumerLag,
+		m.httpDuration,
+		m.dbDuration,
+		m.componentFailures,
+		m.componentRestarts,
+		m.componentUp,
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// MessageConsumed records a fetched message and the partition lag it was fetched at.
+func (m *Metrics) MessageConsumed(topic string, partition int, highWaterMark, offset int64) {
+	m.messagesConsumed.WithLabelValues(topic).Inc()
+	m.consumerLag.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(max(0, highWaterMark-offset-1)))
+}
+
+func (m *Metrics) MessagesCommitted(topic string, count int) {
+	m.messagesCommitted.WithLabelValues(topic).Add(float64(count))
+}
+
+func (m *Metrics) MessageFailed(topic, stage string) {
+	m.messagesFailed.WithLabelValues(topic, stage).Inc()
+}
+
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// ObserveDBOperation records how long a storage operation took since start.
+// Missing and already stored orders are results of their own rather than errors.
+func (m *Metrics) ObserveDBOperation(operation string, start time.Time, err error) {
+	result := "ok"
+	switch {
+	case errors.Is(err, orderErrs.ErrOrderNotFount):
+		result = "not_found"
+	case errors.Is(err, orderErrs.ErrOrderAlreadyExists):
+		result = "duplicate"
+	case err != nil:
+		result = "error"
+	}
+
+	m.dbDuration.WithLabelValues(operation, result).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) ComponentStarted(component string) {
+	m.componentUp.WithLabelValues(component).Set(1)
+}
+
+// ComponentExited records that a component stopped running, counting it as a failure when err is set.
+func (m *Metrics) ComponentExited(component string, err error) {
+	m.componentUp.WithLabelValues(component).Set(0)
+	if err != nil {
+		m.componentFailures.WithLabelValues(component).Inc()
+	}
+}
+
+func (m *Metrics) ComponentRestarted(component string) {
+	m.componentRestarts.WithLabelValues(component).Inc()
+}
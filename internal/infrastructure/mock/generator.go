@@ -4,15 +4,61 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"slices"
 	"time"
 
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
 	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
 )
 
-type Generator struct{}
+type Generator struct {
+	minItems   int
+	maxItems   int
+	currencies weightedChoice
+	locales    weightedChoice
+}
 
+// NewMockGenerator generates orders of 1 to 5 items, in USD with the en locale.
 func NewMockGenerator() *Generator {
-	return &Generator{}
+	return NewGenerator(&config.OrderProfile{MinItems: 1, MaxItems: 5})
+}
+
+// NewGenerator generates orders shaped by the profile.
+func NewGenerator(profile *config.OrderProfile) *Generator {
+	return &Generator{
+		minItems:   profile.MinItems,
+		maxItems:   profile.MaxItems,
+		currencies: newWeightedChoice(profile.Currencies, "USD"),
+		locales:    newWeightedChoice(profile.Locales, "en"),
+	}
+}
+
+// weightedChoice picks values with probability proportional to their weights.
+type weightedChoice struct {
+	values     []string
+	cumulative []int
+}
+
+func newWeightedChoice(weights map[string]int, fallback string) weightedChoice {
+	if len(weights) == 0 {
+		weights = map[string]int{fallback: 1}
+	}
+
+	// Sorted, so the same profile always maps random numbers to the same values.
+	values := make([]string, 0, len(weights))
+	for value := range weights {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+
+	choice := weightedChoice{values: values, cumulative: make([]int, len(values))}
+	total := 0
+	for i, value := range values {
+		total += weights[value]
+		choice.cumulative[i] = total
+	}
+
+	return choice
 }
 
 func (g *Generator) GenerateOrder() dto.Order {
@@ -26,7 +72,7 @@ func (g *Generator) GenerateOrder() dto.Order {
 		Delivery:          g.GenerateDelivery(),
 		Payment:           g.GeneratePayment(orderID),
 		Items:             g.GenerateItems(trackNumber),
-		Locale:            "en",
+		Locale:            g.locales.pick(g),
 		InternalSignature: "",
 		CustomerID:        g.generateRandomString(10),
 		DeliveryService:   "meest",
@@ -53,7 +99,7 @@ func (g *Generator) GeneratePayment(transactionID string) dto.Payment {
 	return dto.Payment{
 		Transaction:  transactionID,
 		RequestID:    "",
-		Currency:     "USD",
+		Currency:     g.currencies.pick(g),
 		Provider:     "wbpay",
 		Amount:       g.generateAmount(1000, 5000),
 		PaymentDt:    time.Now().Unix(),
@@ -65,7 +111,7 @@ func (g *Generator) GeneratePayment(transactionID string) dto.Payment {
 }
 
 func (g *Generator) GenerateItems(trackNumber string) []dto.Item {
-	count := g.randomInt(1, 5)
+	count := g.randomInt(g.minItems, g.maxItems)
 	items := make([]dto.Item, count)
 
 	for i := range items {
@@ -193,6 +239,12 @@ func (g *Generator) randomChoice(options []string) string {
 	return options[num.Int64()]
 }
 
+func (c weightedChoice) pick(g *Generator) string {
+	n := g.randomInt(1, c.cumulative[len(c.cumulative)-1])
+	idx, _ := slices.BinarySearch(c.cumulative, n)
+	return c.values[idx]
+}
+
 func (g *Generator) randomInt(min, max int) int {
 	num, _ := rand.Int(rand.Reader, big.NewInt(int64(max-min+1)))
 	return min + int(num.Int64())
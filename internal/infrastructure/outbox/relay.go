@@ -0,0 +1,196 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	HeaderEventID   = "x-event-id"
+	HeaderEventType = "x-event-type"
+)
+
+// messageWriter is the part of kafka.Writer the relay publishes with.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafkaLib.Message) error
+}
+
+// Relay publishes events stored in the outbox table to the order events topic. Events
+// are claimed for the relay and the claim committed before the Kafka write, then
+// confirmed once the write succeeded; a failed confirmation is retried without
+// publishing again. Only one relay holds claims at a time and every message is keyed by
+// its order, so events of an order reach consumers in the order they were stored.
+// Delivery is still at-least-once: if the relay dies between the write and the
+// confirmation, the events are published again once the claim lease runs out. Each
+// message carries the outbox row id in HeaderEventID, which is the idempotency key
+// consumers must dedupe on.
+type Relay struct {
+	log         appPorts.Logger
+	repo        ports.OutboxRelayRepo
+	writer      messageWriter
+	topic       string
+	owner       string
+	interval    time.Duration
+	batchSize   int
+	lease       time.Duration
+	unconfirmed []int64
+	stopChan    chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewRelay(
+	log appPorts.Logger,
+	cfg *config.Outbox,
+	repo ports.OutboxRelayRepo,
+	writer *kafka.Writer,
+) *Relay {
+	return &Relay{
+		log:       log,
+		repo:      repo,
+		writer:    writer,
+		topic:     writer.GetOrderEventsTopic(),
+		owner:     relayOwner(),
+		interval:  cfg.PollInterval,
+		batchSize: cfg.BatchSize,
+		lease:     cfg.ClaimLease,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// relayOwner names this relay in the claims it takes, unique per process.
+func relayOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "relay"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+func (r *Relay) publish(ctx context.Context, events []model.OutboxEvent) error {
+	// The relay polls on its own, so the span links to the requests that stored
+	// the events instead of starting a trace unrelated to them.
+	links := make([]trace.Link, 0, len(events))
+	for _, event := range events {
+		if link, ok := tracing.LinkTraceParent(event.TraceParent); ok {
+			links = append(links, link)
+		}
+	}
+
+	ctx, span := tracing.StartPublish(ctx, r.topic, len(events), links...)
+	defer span.End()
+
+	messages := make([]kafkaLib.Message, len(events))
+	for i, event := range events {
+		messages[i] = kafkaLib.Message{
+			Topic: r.topic,
+			Key:   []byte(event.AggregateID),
+			Value: event.Payload,
+			Headers: []kafkaLib.Header{
+				{Key: HeaderEventID, Value: []byte(strconv.FormatInt(event.ID, 10))},
+				{Key: HeaderEventType, Value: []byte(event.EventType)},
+			},
+			Time: event.CreatedAt,
+		}
+		tracing.Inject(ctx, &messages[i])
+	}
+
+	if err := r.writer.WriteMessages(ctx, messages...); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *Relay) relay(ctx context.Context) {
+	const op = "outbox.Relay.relay"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "topic", r.topic, "owner", r.owner}, args...)
+	}
+
+	if len(r.unconfirmed) > 0 {
+		if err := r.repo.MarkOutboxEventsSent(ctx, r.unconfirmed); err != nil {
+			r.log.Error("Failed to confirm published outbox events", withFields("error", err.Error())...)
+			return
+		}
+		r.unconfirmed = nil
+	}
+
+	for {
+		events, err := r.repo.ClaimOutboxEvents(ctx, r.owner, r.batchSize, r.lease)
+		if err != nil {
+			r.log.Error("Failed to claim outbox events", withFields("error", err.Error())...)
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+
+		// A failed write keeps the claim, so the same events are claimed again first.
+		if err = r.publish(ctx, events); err != nil {
+			r.log.Error("Failed to publish outbox events", withFields("error", err.Error())...)
+			return
+		}
+
+		ids := make([]int64, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		if err = r.repo.MarkOutboxEventsSent(ctx, ids); err != nil {
+			r.unconfirmed = ids
+			r.log.Error("Failed to confirm published outbox events", withFields("error", err.Error())...)
+			return
+		}
+
+		r.log.Info("Outbox events published", withFields("count", len(events))...)
+		if len(events) < r.batchSize {
+			return
+		}
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	const op = "outbox.Relay.Run"
+
+	if r.topic == "" {
+		return fmt.Errorf("%s: order events topic is not configured", op)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.relay(ctx)
+		case <-r.stopChan:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (r *Relay) Shutdown(_ context.Context) error {
+	r.stopOnce.Do(func() { close(r.stopChan) })
+	return nil
+}
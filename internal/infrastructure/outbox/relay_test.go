@@ -0,0 +1,204 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+type outboxRow struct {
+	event        model.OutboxEvent
+	sent         bool
+	claimedBy    string
+	claimedUntil time.Time
+}
+
+// outboxTable claims and confirms rows the way the Postgres repository does.
+type outboxTable struct {
+	mu         sync.Mutex
+	rows       []*outboxRow
+	markErrors int
+}
+
+func newOutboxTable(aggregates ...string) *outboxTable {
+	table := &outboxTable{}
+	for i, aggregate := range aggregates {
+		table.rows = append(table.rows, &outboxRow{event: model.OutboxEvent{
+			ID:          int64(i + 1),
+			AggregateID: aggregate,
+			EventType:   "order.created",
+			Payload:     []byte(`{}`),
+		}})
+	}
+	return table
+}
+
+func (t *outboxTable) ClaimOutboxEvents(_ context.Context, owner string, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, row := range t.rows {
+		if !row.sent && row.claimedBy != owner && row.claimedUntil.After(now) {
+			return nil, nil
+		}
+	}
+
+	var events []model.OutboxEvent
+	for _, row := range t.rows {
+		if row.sent || len(events) == limit {
+			continue
+		}
+		row.claimedBy, row.claimedUntil = owner, now.Add(lease)
+		events = append(events, row.event)
+	}
+	return events, nil
+}
+
+func (t *outboxTable) MarkOutboxEventsSent(ctx context.Context, ids []int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if t.markErrors > 0 {
+		t.markErrors--
+		return errors.New("connection reset")
+	}
+	for _, row := range t.rows {
+		if slices.Contains(ids, row.event.ID) {
+			row.sent, row.claimedBy = true, ""
+		}
+	}
+	return nil
+}
+
+func (t *outboxTable) pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pending := 0
+	for _, row := range t.rows {
+		if !row.sent {
+			pending++
+		}
+	}
+	return pending
+}
+
+// topic records written messages and runs afterWrite once they are written.
+type topic struct {
+	mu         sync.Mutex
+	messages   []kafkaLib.Message
+	afterWrite func()
+}
+
+func (w *topic) WriteMessages(_ context.Context, messages ...kafkaLib.Message) error {
+	w.mu.Lock()
+	w.messages = append(w.messages, messages...)
+	afterWrite := w.afterWrite
+	w.mu.Unlock()
+
+	if afterWrite != nil {
+		afterWrite()
+	}
+	return nil
+}
+
+// published returns the event id and key of every written message, in write order.
+func (w *topic) published() [][2]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	published := make([][2]string, len(w.messages))
+	for i, message := range w.messages {
+		for _, header := range message.Headers {
+			if header.Key == HeaderEventID {
+				published[i] = [2]string{string(header.Value), string(message.Key)}
+			}
+		}
+	}
+	return published
+}
+
+func newTestRelay(owner string, table *outboxTable, writer *topic, lease time.Duration) *Relay {
+	return &Relay{
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		repo:      table,
+		writer:    writer,
+		topic:     "order-events",
+		owner:     owner,
+		interval:  time.Millisecond,
+		batchSize: 10,
+		lease:     lease,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+func TestRelayKilledBeforeConfirmRepublishesInOrder(t *testing.T) {
+	const lease = 50 * time.Millisecond
+	table := newOutboxTable("a", "b", "a")
+	writer := &topic{}
+
+	// The first relay dies right after its Kafka write, before the rows are marked sent.
+	ctx, kill := context.WithCancel(context.Background())
+	writer.afterWrite = kill
+	newTestRelay("first", table, writer, lease).relay(ctx)
+	writer.afterWrite = nil
+
+	want := [][2]string{{"1", "a"}, {"2", "b"}, {"3", "a"}}
+	if got := writer.published(); !slices.Equal(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	if pending := table.pending(); pending != 3 {
+		t.Fatalf("pending = %d, want 3 after the relay died", pending)
+	}
+
+	// Another replica must not overtake the live claim of the dead one.
+	second := newTestRelay("second", table, writer, lease)
+	second.relay(context.Background())
+	if got := writer.published(); len(got) != 3 {
+		t.Fatalf("published %v while the claim was live, want nothing new", got[3:])
+	}
+
+	time.Sleep(2 * lease)
+	second.relay(context.Background())
+
+	want = append(want, want...)
+	if got := writer.published(); !slices.Equal(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	if pending := table.pending(); pending != 0 {
+		t.Fatalf("pending = %d, want 0", pending)
+	}
+}
+
+func TestRelayRetriesConfirmWithoutRepublishing(t *testing.T) {
+	table := newOutboxTable("a", "b")
+	table.markErrors = 1
+	writer := &topic{}
+	relay := newTestRelay("only", table, writer, time.Minute)
+
+	relay.relay(context.Background())
+	if pending := table.pending(); pending != 2 {
+		t.Fatalf("pending = %d, want 2 after a failed confirmation", pending)
+	}
+
+	relay.relay(context.Background())
+	if pending := table.pending(); pending != 0 {
+		t.Fatalf("pending = %d, want 0", pending)
+	}
+	if got := writer.published(); len(got) != 2 {
+		t.Fatalf("published %v, want each event once", got)
+	}
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS trace_parent TEXT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS trace_parent;
+
+-- +goose StatementEnd
@@ -4,9 +4,12 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
@@ -16,18 +19,28 @@ import (
 type Pool struct {
 	*pgxpool.Pool
 	migrations bool
+	migrated   atomic.Bool
 }
 
 func NewPool(ctx context.Context, config *config.Postgres) *Pool {
-	pool, err := pgxpool.New(ctx, config.ConnectionString())
+	poolConfig, err := pgxpool.ParseConfig(config.ConnectionString())
 	if err != nil {
 		panic(err)
 	}
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
 
-	return &Pool{
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	p := &Pool{
 		Pool:       pool,
 		migrations: config.Migrations,
 	}
+	p.migrated.Store(!config.Migrations)
+
+	return p
 }
 
 //go:embed migrations/*.sql
@@ -57,6 +70,7 @@ func (p *Pool) Run(ctx context.Context) error {
 		if err := p.migrate(); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
+		p.migrated.Store(true)
 	}
 
 	ticker := time.NewTicker(30 * time.Second)
@@ -74,6 +88,17 @@ func (p *Pool) Run(ctx context.Context) error {
 	}
 }
 
+// HealthCheck reports the pool ready once migrations are applied and while Postgres answers pings.
+func (p *Pool) HealthCheck(ctx context.Context) appPorts.Health {
+	if !p.migrated.Load() {
+		return appPorts.NotReady("migrations are not applied yet")
+	}
+	if err := p.Pool.Ping(ctx); err != nil {
+		return appPorts.NotReady("ping failed: " + err.Error())
+	}
+	return appPorts.Up()
+}
+
 func (p *Pool) Shutdown(_ context.Context) error {
 	p.Pool.Close()
 	return nil
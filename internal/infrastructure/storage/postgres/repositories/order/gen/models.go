@@ -49,6 +49,24 @@ type Order struct {
 	OofShard          pgtype.Text      `json:"oof_shard"`
 }
 
+type OrderRead struct {
+	OrderUid   string           `json:"order_uid"`
+	ReadCount  int64            `json:"read_count"`
+	LastReadAt pgtype.Timestamp `json:"last_read_at"`
+}
+
+type Outbox struct {
+	ID           int64            `json:"id"`
+	AggregateID  string           `json:"aggregate_id"`
+	EventType    string           `json:"event_type"`
+	Payload      []byte           `json:"payload"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	SentAt       pgtype.Timestamp `json:"sent_at"`
+	TraceParent  pgtype.Text      `json:"trace_parent"`
+	ClaimedBy    pgtype.Text      `json:"claimed_by"`
+	ClaimedUntil pgtype.Timestamp `json:"claimed_until"`
+}
+
 type Payment struct {
 	OrderUid      string      `json:"order_uid"`
 	TransactionID string      `json:"transaction_id"`
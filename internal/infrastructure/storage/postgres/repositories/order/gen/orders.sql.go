@@ -243,6 +243,16 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) er
 	return err
 }
 
+const deleteItems = `-- name: DeleteItems :exec
+DELETE FROM items
+WHERE order_uid = $1
+`
+
+func (q *Queries) DeleteItems(ctx context.Context, orderUid string) error {
+	_, err := q.db.Exec(ctx, deleteItems, orderUid)
+	return err
+}
+
 const getAllOrders = `-- name: GetAllOrders :many
 SELECT order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard FROM orders
 `
@@ -279,6 +289,54 @@ func (q *Queries) GetAllOrders(ctx context.Context) ([]Order, error) {
 	return items, nil
 }
 
+const getCustomerOrderSummary = `-- name: GetCustomerOrderSummary :many
+SELECT
+    p.currency,
+    COUNT(*)::bigint AS order_count,
+    COALESCE(SUM(p.amount), 0)::bigint AS total_amount,
+    MIN(o.date_created)::timestamp AS first_order_at,
+    MAX(o.date_created)::timestamp AS last_order_at
+FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE o.customer_id = $1
+GROUP BY p.currency
+ORDER BY p.currency
+`
+
+type GetCustomerOrderSummaryRow struct {
+	Currency     pgtype.Text      `json:"currency"`
+	OrderCount   int64            `json:"order_count"`
+	TotalAmount  int64            `json:"total_amount"`
+	FirstOrderAt pgtype.Timestamp `json:"first_order_at"`
+	LastOrderAt  pgtype.Timestamp `json:"last_order_at"`
+}
+
+func (q *Queries) GetCustomerOrderSummary(ctx context.Context, customerID string) ([]GetCustomerOrderSummaryRow, error) {
+	rows, err := q.db.Query(ctx, getCustomerOrderSummary, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCustomerOrderSummaryRow
+	for rows.Next() {
+		var i GetCustomerOrderSummaryRow
+		if err := rows.Scan(
+			&i.Currency,
+			&i.OrderCount,
+			&i.TotalAmount,
+			&i.FirstOrderAt,
+			&i.LastOrderAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getDeliveriesForOrders = `-- name: GetDeliveriesForOrders :many
 SELECT order_uid, del_name, phone, zip, city, address, region, email FROM deliveries
 WHERE order_uid = ANY($1::text[])
@@ -335,6 +393,31 @@ func (q *Queries) GetDelivery(ctx context.Context, orderUid string) (Delivery, e
 	return i, err
 }
 
+const getExistingOrderUIDs = `-- name: GetExistingOrderUIDs :many
+SELECT order_uid FROM orders
+WHERE order_uid = ANY($1::text[])
+`
+
+func (q *Queries) GetExistingOrderUIDs(ctx context.Context, ids []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getExistingOrderUIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var order_uid string
+		if err := rows.Scan(&order_uid); err != nil {
+			return nil, err
+		}
+		items = append(items, order_uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getItems = `-- name: GetItems :many
 SELECT id, order_uid, chrt_id, track_number, price, rid, item_name, sale, item_size, total_price, nm_id, brand, status FROM items
 WHERE order_uid = $1
@@ -476,6 +559,87 @@ func (q *Queries) GetOrder(ctx context.Context, orderUid string) (Order, error)
 	return i, err
 }
 
+const getOrdersByTrackNumber = `-- name: GetOrdersByTrackNumber :many
+SELECT order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard FROM orders
+WHERE track_number = $1
+   OR order_uid IN (
+       SELECT items.order_uid FROM items
+       WHERE items.track_number = $1
+   )
+ORDER BY date_created DESC
+`
+
+func (q *Queries) GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByTrackNumber, trackNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.OrderUid,
+			&i.TrackNumber,
+			&i.Entry,
+			&i.Locale,
+			&i.InternalSignature,
+			&i.CustomerID,
+			&i.DeliveryService,
+			&i.Shardkey,
+			&i.SmID,
+			&i.DateCreated,
+			&i.OofShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByTransactionID = `-- name: GetOrdersByTransactionID :many
+SELECT o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature, o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE p.transaction_id = $1
+ORDER BY o.date_created DESC
+`
+
+func (q *Queries) GetOrdersByTransactionID(ctx context.Context, transactionID string) ([]Order, error) {
+	rows, err := q.db.Query(ctx, getOrdersByTransactionID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.OrderUid,
+			&i.TrackNumber,
+			&i.Entry,
+			&i.Locale,
+			&i.InternalSignature,
+			&i.CustomerID,
+			&i.DeliveryService,
+			&i.Shardkey,
+			&i.SmID,
+			&i.DateCreated,
+			&i.OofShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPayment = `-- name: GetPayment :one
 SELECT order_uid, transaction_id, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee FROM payments
 WHERE order_uid = $1
@@ -537,3 +701,294 @@ func (q *Queries) GetPaymentsForOrders(ctx context.Context, ids []string) ([]Pay
 	}
 	return items, nil
 }
+
+const listOrdersAsc = `-- name: ListOrdersAsc :many
+SELECT o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature, o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE ($1::text IS NULL OR o.customer_id = $1)
+  AND ($2::text IS NULL OR o.track_number = $2)
+  AND ($3::text IS NULL OR o.delivery_service = $3)
+  AND ($4::text IS NULL OR o.locale = $4)
+  AND ($5::text IS NULL OR p.currency = $5)
+  AND ($6::text IS NULL OR p.provider = $6)
+  AND ($7::text IS NULL OR p.bank = $7)
+  AND ($8::timestamp IS NULL OR o.date_created >= $8)
+  AND ($9::timestamp IS NULL OR o.date_created < $9)
+  AND ($10::timestamp IS NULL
+    OR (o.date_created, o.order_uid) > ($10, $11::text))
+ORDER BY o.date_created ASC, o.order_uid ASC
+LIMIT $12
+`
+
+type ListOrdersAscParams struct {
+	CustomerID      pgtype.Text      `json:"customer_id"`
+	TrackNumber     pgtype.Text      `json:"track_number"`
+	DeliveryService pgtype.Text      `json:"delivery_service"`
+	Locale          pgtype.Text      `json:"locale"`
+	Currency        pgtype.Text      `json:"currency"`
+	Provider        pgtype.Text      `json:"provider"`
+	Bank            pgtype.Text      `json:"bank"`
+	CreatedFrom     pgtype.Timestamp `json:"created_from"`
+	CreatedTo       pgtype.Timestamp `json:"created_to"`
+	CursorDate      pgtype.Timestamp `json:"cursor_date"`
+	CursorUid       pgtype.Text      `json:"cursor_uid"`
+	PageSize        int32            `json:"page_size"`
+}
+
+func (q *Queries) ListOrdersAsc(ctx context.Context, arg ListOrdersAscParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listOrdersAsc,
+		arg.CustomerID,
+		arg.TrackNumber,
+		arg.DeliveryService,
+		arg.Locale,
+		arg.Currency,
+		arg.Provider,
+		arg.Bank,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorDate,
+		arg.CursorUid,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.OrderUid,
+			&i.TrackNumber,
+			&i.Entry,
+			&i.Locale,
+			&i.InternalSignature,
+			&i.CustomerID,
+			&i.DeliveryService,
+			&i.Shardkey,
+			&i.SmID,
+			&i.DateCreated,
+			&i.OofShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrdersDesc = `-- name: ListOrdersDesc :many
+SELECT o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature, o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE ($1::text IS NULL OR o.customer_id = $1)
+  AND ($2::text IS NULL OR o.track_number = $2)
+  AND ($3::text IS NULL OR o.delivery_service = $3)
+  AND ($4::text IS NULL OR o.locale = $4)
+  AND ($5::text IS NULL OR p.currency = $5)
+  AND ($6::text IS NULL OR p.provider = $6)
+  AND ($7::text IS NULL OR p.bank = $7)
+  AND ($8::timestamp IS NULL OR o.date_created >= $8)
+  AND ($9::timestamp IS NULL OR o.date_created < $9)
+  AND ($10::timestamp IS NULL
+    OR (o.date_created, o.order_uid) < ($10, $11::text))
+ORDER BY o.date_created DESC, o.order_uid DESC
+LIMIT $12
+`
+
+type ListOrdersDescParams struct {
+	CustomerID      pgtype.Text      `json:"customer_id"`
+	TrackNumber     pgtype.Text      `json:"track_number"`
+	DeliveryService pgtype.Text      `json:"delivery_service"`
+	Locale          pgtype.Text      `json:"locale"`
+	Currency        pgtype.Text      `json:"currency"`
+	Provider        pgtype.Text      `json:"provider"`
+	Bank            pgtype.Text      `json:"bank"`
+	CreatedFrom     pgtype.Timestamp `json:"created_from"`
+	CreatedTo       pgtype.Timestamp `json:"created_to"`
+	CursorDate      pgtype.Timestamp `json:"cursor_date"`
+	CursorUid       pgtype.Text      `json:"cursor_uid"`
+	PageSize        int32            `json:"page_size"`
+}
+
+func (q *Queries) ListOrdersDesc(ctx context.Context, arg ListOrdersDescParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listOrdersDesc,
+		arg.CustomerID,
+		arg.TrackNumber,
+		arg.DeliveryService,
+		arg.Locale,
+		arg.Currency,
+		arg.Provider,
+		arg.Bank,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorDate,
+		arg.CursorUid,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.OrderUid,
+			&i.TrackNumber,
+			&i.Entry,
+			&i.Locale,
+			&i.InternalSignature,
+			&i.CustomerID,
+			&i.DeliveryService,
+			&i.Shardkey,
+			&i.SmID,
+			&i.DateCreated,
+			&i.OofShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateDelivery = `-- name: UpdateDelivery :exec
+UPDATE deliveries SET
+    del_name = $2,
+    phone = $3,
+    zip = $4,
+    city = $5,
+    address = $6,
+    region = $7,
+    email = $8
+WHERE order_uid = $1
+`
+
+type UpdateDeliveryParams struct {
+	OrderUid string      `json:"order_uid"`
+	DelName  string      `json:"del_name"`
+	Phone    string      `json:"phone"`
+	Zip      pgtype.Text `json:"zip"`
+	City     pgtype.Text `json:"city"`
+	Address  pgtype.Text `json:"address"`
+	Region   pgtype.Text `json:"region"`
+	Email    pgtype.Text `json:"email"`
+}
+
+func (q *Queries) UpdateDelivery(ctx context.Context, arg UpdateDeliveryParams) error {
+	_, err := q.db.Exec(ctx, updateDelivery,
+		arg.OrderUid,
+		arg.DelName,
+		arg.Phone,
+		arg.Zip,
+		arg.City,
+		arg.Address,
+		arg.Region,
+		arg.Email,
+	)
+	return err
+}
+
+const updateOrder = `-- name: UpdateOrder :execrows
+UPDATE orders SET
+    track_number = $1,
+    entry = $2,
+    locale = $3,
+    internal_signature = $4,
+    customer_id = $5,
+    delivery_service = $6,
+    shardkey = $7,
+    sm_id = $8,
+    date_created = $9,
+    oof_shard = $10
+WHERE order_uid = $11
+  AND (NOT $12::bool OR date_created IS NULL OR date_created < $9)
+`
+
+type UpdateOrderParams struct {
+	TrackNumber       string           `json:"track_number"`
+	Entry             string           `json:"entry"`
+	Locale            string           `json:"locale"`
+	InternalSignature pgtype.Text      `json:"internal_signature"`
+	CustomerID        string           `json:"customer_id"`
+	DeliveryService   pgtype.Text      `json:"delivery_service"`
+	Shardkey          pgtype.Text      `json:"shardkey"`
+	SmID              int32            `json:"sm_id"`
+	DateCreated       pgtype.Timestamp `json:"date_created"`
+	OofShard          pgtype.Text      `json:"oof_shard"`
+	OrderUid          string           `json:"order_uid"`
+	OnlyIfNewer       bool             `json:"only_if_newer"`
+}
+
+func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateOrder,
+		arg.TrackNumber,
+		arg.Entry,
+		arg.Locale,
+		arg.InternalSignature,
+		arg.CustomerID,
+		arg.DeliveryService,
+		arg.Shardkey,
+		arg.SmID,
+		arg.DateCreated,
+		arg.OofShard,
+		arg.OrderUid,
+		arg.OnlyIfNewer,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updatePayment = `-- name: UpdatePayment :exec
+UPDATE payments SET
+    transaction_id = $2,
+    request_id = $3,
+    currency = $4,
+    provider = $5,
+    amount = $6,
+    payment_dt = $7,
+    bank = $8,
+    delivery_cost = $9,
+    goods_total = $10,
+    custom_fee = $11
+WHERE order_uid = $1
+`
+
+type UpdatePaymentParams struct {
+	OrderUid      string      `json:"order_uid"`
+	TransactionID string      `json:"transaction_id"`
+	RequestID     pgtype.Text `json:"request_id"`
+	Currency      pgtype.Text `json:"currency"`
+	Provider      pgtype.Text `json:"provider"`
+	Amount        pgtype.Int4 `json:"amount"`
+	PaymentDt     pgtype.Int8 `json:"payment_dt"`
+	Bank          pgtype.Text `json:"bank"`
+	DeliveryCost  pgtype.Int4 `json:"delivery_cost"`
+	GoodsTotal    pgtype.Int4 `json:"goods_total"`
+	CustomFee     pgtype.Int4 `json:"custom_fee"`
+}
+
+func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) error {
+	_, err := q.db.Exec(ctx, updatePayment,
+		arg.OrderUid,
+		arg.TransactionID,
+		arg.RequestID,
+		arg.Currency,
+		arg.Provider,
+		arg.Amount,
+		arg.PaymentDt,
+		arg.Bank,
+		arg.DeliveryCost,
+		arg.GoodsTotal,
+		arg.CustomFee,
+	)
+	return err
+}
@@ -9,19 +9,36 @@ import (
 )
 
 type Querier interface {
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]Outbox, error)
 	CreateDelivery(ctx context.Context, arg CreateDeliveryParams) error
 	CreateItem(ctx context.Context, arg CreateItemParams) error
 	CreateOrder(ctx context.Context, arg CreateOrderParams) error
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) error
+	DeleteItems(ctx context.Context, orderUid string) error
 	GetAllOrders(ctx context.Context) ([]Order, error)
+	GetCustomerOrderSummary(ctx context.Context, customerID string) ([]GetCustomerOrderSummaryRow, error)
 	GetDeliveriesForOrders(ctx context.Context, ids []string) ([]Delivery, error)
 	GetDelivery(ctx context.Context, orderUid string) (Delivery, error)
+	GetExistingOrderUIDs(ctx context.Context, ids []string) ([]string, error)
 	GetItems(ctx context.Context, orderUid string) ([]Item, error)
 	GetItemsForOrders(ctx context.Context, ids []string) ([]Item, error)
 	GetLatestOrders(ctx context.Context, limit int32) ([]Order, error)
+	GetMostReadOrders(ctx context.Context, limit int32) ([]Order, error)
 	GetOrder(ctx context.Context, orderUid string) (Order, error)
+	GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]Order, error)
+	GetOrdersByTransactionID(ctx context.Context, transactionID string) ([]Order, error)
 	GetPayment(ctx context.Context, orderUid string) (Payment, error)
 	GetPaymentsForOrders(ctx context.Context, ids []string) ([]Payment, error)
+	HasForeignOutboxClaims(ctx context.Context, owner string) (bool, error)
+	ListOrdersAsc(ctx context.Context, arg ListOrdersAscParams) ([]Order, error)
+	ListOrdersDesc(ctx context.Context, arg ListOrdersDescParams) ([]Order, error)
+	LockOutboxRelay(ctx context.Context, lockKey int64) (bool, error)
+	MarkOutboxEventsSent(ctx context.Context, ids []int64) error
+	RecordOrderReads(ctx context.Context, arg RecordOrderReadsParams) error
+	UpdateDelivery(ctx context.Context, arg UpdateDeliveryParams) error
+	UpdateOrder(ctx context.Context, arg UpdateOrderParams) (int64, error)
+	UpdatePayment(ctx context.Context, arg UpdatePaymentParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,138 @@
+package order
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/repositories/order/gen"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func createOutboxEvent(ctx context.Context, qtx *gen.Queries, eventType string, order *model.Order) error {
+	payload, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	return qtx.CreateOutboxEvent(ctx, gen.CreateOutboxEventParams{
+		AggregateID: order.OrderUID,
+		EventType:   eventType,
+		Payload:     payload,
+		TraceParent: traceParent(ctx),
+	})
+}
+
+func copyOutboxEvents(ctx context.Context, tx pgx.Tx, eventType string, orders []*model.Order) error {
+	parent := traceParent(ctx)
+	rows := make([][]any, len(orders))
+	for i, order := range orders {
+		payload, err := json.Marshal(order)
+		if err != nil {
+			return err
+		}
+		rows[i] = []any{order.OrderUID, eventType, payload, parent}
+	}
+
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"outbox"},
+		[]string{"aggregate_id", "event_type", "payload", "trace_parent"},
+		pgx.CopyFromRows(rows),
+	)
+	return err
+}
+
+// traceParent keeps the trace of the write with its events, so the relay can link
+// the publish back to it.
+func traceParent(ctx context.Context) pgtype.Text {
+	parent := tracing.TraceParent(ctx)
+	return pgtype.Text{String: parent, Valid: parent != ""}
+}
+
+// outboxRelayLockKey is the advisory lock that serializes claims of all relays.
+const outboxRelayLockKey int64 = 0x6f7574626f78 // "outbox"
+
+// ClaimOutboxEvents leases up to limit of the oldest pending events to owner and commits
+// the claim before they are published, so no transaction stays open across the Kafka
+// write. Claims are serialized by an advisory lock, and nothing is claimed while another
+// owner's lease is live: events go out in id order, one relay at a time. Events whose
+// lease ran out unconfirmed are claimed again, so delivery is at-least-once.
+func (r *Repository) ClaimOutboxEvents(
+	ctx context.Context,
+	owner string,
+	limit int,
+	lease time.Duration,
+) ([]model.OutboxEvent, error) {
+	const op = "repositories.order.ClaimOutboxEvents"
+
+	tx, err := r.executor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := r.queries.WithTx(tx)
+
+	locked, err := qtx.LockOutboxRelay(ctx, outboxRelayLockKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to take relay lock: %w", op, err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	busy, err := qtx.HasForeignOutboxClaims(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to check claims: %w", op, err)
+	}
+	if busy {
+		return nil, nil
+	}
+
+	eventsDB, err := qtx.ClaimOutboxEvents(ctx, gen.ClaimOutboxEventsParams{
+		Owner:      owner,
+		Lease:      pgtype.Interval{Microseconds: lease.Microseconds(), Valid: true},
+		BatchLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to claim events: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit tx: %w", op, err)
+	}
+
+	// UPDATE ... RETURNING does not keep the order of the subquery.
+	slices.SortFunc(eventsDB, func(a, b gen.Outbox) int { return cmp.Compare(a.ID, b.ID) })
+
+	events := make([]model.OutboxEvent, len(eventsDB))
+	for i, event := range eventsDB {
+		events[i] = model.OutboxEvent{
+			ID:          event.ID,
+			AggregateID: event.AggregateID,
+			EventType:   event.EventType,
+			Payload:     event.Payload,
+			CreatedAt:   event.CreatedAt.Time,
+			TraceParent: event.TraceParent.String,
+		}
+	}
+
+	return events, nil
+}
+
+func (r *Repository) MarkOutboxEventsSent(ctx context.Context, ids []int64) error {
+	const op = "repositories.order.MarkOutboxEventsSent"
+
+	if err := r.queries.MarkOutboxEventsSent(ctx, ids); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -143,3 +143,112 @@ WHERE order_uid = ANY(@ids::text[]);
 SELECT * FROM payments
 WHERE order_uid = ANY(@ids::text[]);
 
+-- name: GetExistingOrderUIDs :many
+SELECT order_uid FROM orders
+WHERE order_uid = ANY(@ids::text[]);
+
+-- name: UpdateOrder :execrows
+UPDATE orders SET
+    track_number = @track_number,
+    entry = @entry,
+    locale = @locale,
+    internal_signature = @internal_signature,
+    customer_id = @customer_id,
+    delivery_service = @delivery_service,
+    shardkey = @shardkey,
+    sm_id = @sm_id,
+    date_created = @date_created,
+    oof_shard = @oof_shard
+WHERE order_uid = @order_uid
+  AND (NOT @only_if_newer::bool OR date_created IS NULL OR date_created < @date_created);
+
+-- name: UpdateDelivery :exec
+UPDATE deliveries SET
+    del_name = $2,
+    phone = $3,
+    zip = $4,
+    city = $5,
+    address = $6,
+    region = $7,
+    email = $8
+WHERE order_uid = $1;
+
+-- name: UpdatePayment :exec
+UPDATE payments SET
+    transaction_id = $2,
+    request_id = $3,
+    currency = $4,
+    provider = $5,
+    amount = $6,
+    payment_dt = $7,
+    bank = $8,
+    delivery_cost = $9,
+    goods_total = $10,
+    custom_fee = $11
+WHERE order_uid = $1;
+
+-- name: DeleteItems :exec
+DELETE FROM items
+WHERE order_uid = $1;
+
+-- name: ListOrdersAsc :many
+SELECT o.* FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE (sqlc.narg('customer_id')::text IS NULL OR o.customer_id = sqlc.narg('customer_id'))
+  AND (sqlc.narg('track_number')::text IS NULL OR o.track_number = sqlc.narg('track_number'))
+  AND (sqlc.narg('delivery_service')::text IS NULL OR o.delivery_service = sqlc.narg('delivery_service'))
+  AND (sqlc.narg('locale')::text IS NULL OR o.locale = sqlc.narg('locale'))
+  AND (sqlc.narg('currency')::text IS NULL OR p.currency = sqlc.narg('currency'))
+  AND (sqlc.narg('provider')::text IS NULL OR p.provider = sqlc.narg('provider'))
+  AND (sqlc.narg('bank')::text IS NULL OR p.bank = sqlc.narg('bank'))
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR o.date_created >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR o.date_created < sqlc.narg('created_to'))
+  AND (sqlc.narg('cursor_date')::timestamp IS NULL
+    OR (o.date_created, o.order_uid) > (sqlc.narg('cursor_date'), sqlc.narg('cursor_uid')::text))
+ORDER BY o.date_created ASC, o.order_uid ASC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListOrdersDesc :many
+SELECT o.* FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE (sqlc.narg('customer_id')::text IS NULL OR o.customer_id = sqlc.narg('customer_id'))
+  AND (sqlc.narg('track_number')::text IS NULL OR o.track_number = sqlc.narg('track_number'))
+  AND (sqlc.narg('delivery_service')::text IS NULL OR o.delivery_service = sqlc.narg('delivery_service'))
+  AND (sqlc.narg('locale')::text IS NULL OR o.locale = sqlc.narg('locale'))
+  AND (sqlc.narg('currency')::text IS NULL OR p.currency = sqlc.narg('currency'))
+  AND (sqlc.narg('provider')::text IS NULL OR p.provider = sqlc.narg('provider'))
+  AND (sqlc.narg('bank')::text IS NULL OR p.bank = sqlc.narg('bank'))
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR o.date_created >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR o.date_created < sqlc.narg('created_to'))
+  AND (sqlc.narg('cursor_date')::timestamp IS NULL
+    OR (o.date_created, o.order_uid) < (sqlc.narg('cursor_date'), sqlc.narg('cursor_uid')::text))
+ORDER BY o.date_created DESC, o.order_uid DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: GetOrdersByTrackNumber :many
+SELECT * FROM orders
+WHERE track_number = @track_number
+   OR order_uid IN (
+       SELECT items.order_uid FROM items
+       WHERE items.track_number = @track_number
+   )
+ORDER BY date_created DESC;
+
+-- name: GetOrdersByTransactionID :many
+SELECT o.* FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE p.transaction_id = $1
+ORDER BY o.date_created DESC;
+
+-- name: GetCustomerOrderSummary :many
+SELECT
+    p.currency,
+    COUNT(*)::bigint AS order_count,
+    COALESCE(SUM(p.amount), 0)::bigint AS total_amount,
+    MIN(o.date_created)::timestamp AS first_order_at,
+    MAX(o.date_created)::timestamp AS last_order_at
+FROM orders o
+JOIN payments p ON p.order_uid = o.order_uid
+WHERE o.customer_id = $1
+GROUP BY p.currency
+ORDER BY p.currency;
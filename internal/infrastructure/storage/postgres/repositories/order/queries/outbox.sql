@@ -0,0 +1,43 @@
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (
+    aggregate_id,
+    event_type,
+    payload,
+    trace_parent
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+);
+
+-- name: LockOutboxRelay :one
+SELECT pg_try_advisory_xact_lock(@lock_key::bigint);
+
+-- name: HasForeignOutboxClaims :one
+SELECT EXISTS (
+    SELECT 1 FROM outbox
+    WHERE sent_at IS NULL
+      AND claimed_by <> @owner::text
+      AND claimed_until > NOW()
+);
+
+-- name: ClaimOutboxEvents :many
+UPDATE outbox
+SET claimed_by = @owner::text,
+    claimed_until = NOW() + @lease::interval
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE sent_at IS NULL
+    ORDER BY id
+    LIMIT @batch_limit::int
+)
+RETURNING *;
+
+-- name: MarkOutboxEventsSent :exec
+UPDATE outbox
+SET sent_at = NOW(),
+    claimed_by = NULL,
+    claimed_until = NULL
+WHERE id = ANY(@ids::bigint[])
+  AND sent_at IS NULL;
@@ -0,0 +1,14 @@
+-- name: RecordOrderReads :exec
+INSERT INTO order_reads (order_uid, read_count, last_read_at)
+SELECT r.order_uid, r.read_count, NOW()
+FROM unnest(@order_uids::text[], @read_counts::bigint[]) AS r(order_uid, read_count)
+JOIN orders o ON o.order_uid = r.order_uid
+ON CONFLICT (order_uid) DO UPDATE
+SET read_count = order_reads.read_count + EXCLUDED.read_count,
+    last_read_at = EXCLUDED.last_read_at;
+
+-- name: GetMostReadOrders :many
+SELECT o.* FROM orders o
+JOIN order_reads r ON r.order_uid = o.order_uid
+ORDER BY r.read_count DESC, r.last_read_at DESC
+LIMIT $1;
@@ -0,0 +1,49 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/repositories/order/gen"
+)
+
+func (r *Repository) GetMostReadOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
+	const op = "repositories.order.GetMostReadOrdersForCache"
+
+	orders, err := r.getOrdersBy(ctx, op, func(qtx *gen.Queries) ([]gen.Order, error) {
+		return qtx.GetMostReadOrders(ctx, int32(limit))
+	})
+	if errors.Is(err, orderErrs.ErrOrderNotFount) {
+		return []*model.Order{}, nil
+	}
+
+	return orders, err
+}
+
+// RecordOrderReads adds reads to the stored per-order counters.
+// Reads of orders that do not exist are ignored.
+func (r *Repository) RecordOrderReads(ctx context.Context, reads map[string]int64) error {
+	const op = "repositories.order.RecordOrderReads"
+
+	if len(reads) == 0 {
+		return nil
+	}
+
+	params := gen.RecordOrderReadsParams{
+		OrderUids:  make([]string, 0, len(reads)),
+		ReadCounts: make([]int64, 0, len(reads)),
+	}
+	for orderUID, count := range reads {
+		params.OrderUids = append(params.OrderUids, orderUID)
+		params.ReadCounts = append(params.ReadCounts, count)
+	}
+
+	if err := r.queries.RecordOrderReads(ctx, params); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/repositories/order/gen"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/tools"
@@ -17,16 +19,30 @@ import (
 type Repository struct {
 	executor *postgres.Pool
 	queries  *gen.Queries
+	metrics  *metrics.Metrics
 }
 
-func NewOrderRepo(executor *postgres.Pool) *Repository {
+func NewOrderRepo(executor *postgres.Pool, metrics *metrics.Metrics) *Repository {
 	return &Repository{
 		executor: executor,
 		queries:  gen.New(executor),
+		metrics:  metrics,
 	}
 }
 
 func (r *Repository) GetOrder(ctx context.Context, orderUID string) (*model.Order, error) {
+	ctx, span := startSpan(ctx, "repositories.order.GetOrder", orderUID)
+	defer span.End()
+
+	start := time.Now()
+	order, err := r.getOrder(ctx, orderUID)
+	r.metrics.ObserveDBOperation("GetOrder", start, err)
+	endSpan(span, err)
+
+	return order, tools.MarkRetryable(err)
+}
+
+func (r *Repository) getOrder(ctx context.Context, orderUID string) (*model.Order, error) {
 	const op = "repositories.order.GetOrder"
 
 	tx, err := r.executor.Begin(ctx)
@@ -125,6 +141,18 @@ func (r *Repository) GetOrder(ctx context.Context, orderUID string) (*model.Orde
 }
 
 func (r *Repository) CreateOrder(ctx context.Context, order *model.Order) error {
+	ctx, span := startSpan(ctx, "repositories.order.CreateOrder", order.OrderUID)
+	defer span.End()
+
+	start := time.Now()
+	err := r.createOrder(ctx, order)
+	r.metrics.ObserveDBOperation("CreateOrder", start, err)
+	endSpan(span, err)
+
+	return tools.MarkRetryable(err)
+}
+
+func (r *Repository) createOrder(ctx context.Context, order *model.Order) error {
 	const op = "repositories.order.CreateOrder"
 
 	tx, err := r.executor.Begin(ctx)
@@ -155,6 +183,139 @@ func (r *Repository) CreateOrder(ctx context.Context, order *model.Order) error
 		return fmt.Errorf("%s: faield to create order: %w", op, err)
 	}
 
+	if err = insertItems(ctx, tx, order); err != nil {
+		return fmt.Errorf("%s: failed to insert items: %w", op, err)
+	}
+
+	err = qtx.CreateDelivery(ctx, gen.CreateDeliveryParams{
+		OrderUid: order.OrderUID,
+		DelName:  order.Delivery.Name,
+		Phone:    order.Delivery.Phone,
+		Zip:      tools.ToText(order.Delivery.Zip),
+		City:     tools.ToText(order.Delivery.City),
+		Address:  tools.ToText(order.Delivery.Address),
+		Region:   tools.ToText(order.Delivery.Region),
+		Email:    tools.ToText(order.Delivery.Email),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: failed to create delivery: %w", op, err)
+	}
+
+	err = qtx.CreatePayment(ctx, gen.CreatePaymentParams{
+		OrderUid:      order.OrderUID,
+		TransactionID: order.Payment.Transaction,
+		RequestID:     tools.ToText(order.Payment.RequestID),
+		Currency:      tools.ToText(order.Payment.Currency),
+		Provider:      tools.ToText(order.Payment.Provider),
+		Amount:        tools.ToInt4(order.Payment.Amount),
+		PaymentDt:     tools.ToInt8(order.Payment.PaymentDt),
+		Bank:          tools.ToText(order.Payment.Bank),
+		DeliveryCost:  tools.ToInt4(order.Payment.DeliveryCost),
+		GoodsTotal:    tools.ToInt4(order.Payment.GoodsTotal),
+		CustomFee:     tools.ToInt4(order.Payment.CustomFee),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: failed to create payment: %w", op, err)
+	}
+
+	if err = createOutboxEvent(ctx, qtx, model.EventOrderCreated, order); err != nil {
+		return fmt.Errorf("%s: failed to create outbox event: %w", op, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateOrder replaces a stored order with the given one. With onlyIfNewer the
+// replacement happens only when the stored date_created is older. It reports
+// whether the order was replaced.
+func (r *Repository) UpdateOrder(ctx context.Context, order *model.Order, onlyIfNewer bool) (bool, error) {
+	updated, err := r.updateOrder(ctx, order, onlyIfNewer)
+	return updated, tools.MarkRetryable(err)
+}
+
+func (r *Repository) updateOrder(ctx context.Context, order *model.Order, onlyIfNewer bool) (bool, error) {
+	const op = "repositories.order.UpdateOrder"
+
+	tx, err := r.executor.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := r.queries.WithTx(tx)
+
+	updated, err := qtx.UpdateOrder(ctx, gen.UpdateOrderParams{
+		OrderUid:          order.OrderUID,
+		TrackNumber:       order.TrackNumber,
+		Entry:             order.Entry,
+		Locale:            order.Locale,
+		InternalSignature: tools.ToText(order.InternalSignature),
+		CustomerID:        order.CustomerID,
+		DeliveryService:   tools.ToText(order.DeliveryService),
+		Shardkey:          tools.ToText(order.ShardKey),
+		SmID:              order.SmID,
+		DateCreated:       tools.ToTimestamp(order.DateCreated),
+		OofShard:          tools.ToText(order.OofShard),
+		OnlyIfNewer:       onlyIfNewer,
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to update order: %w", op, err)
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	err = qtx.UpdateDelivery(ctx, gen.UpdateDeliveryParams{
+		OrderUid: order.OrderUID,
+		DelName:  order.Delivery.Name,
+		Phone:    order.Delivery.Phone,
+		Zip:      tools.ToText(order.Delivery.Zip),
+		City:     tools.ToText(order.Delivery.City),
+		Address:  tools.ToText(order.Delivery.Address),
+		Region:   tools.ToText(order.Delivery.Region),
+		Email:    tools.ToText(order.Delivery.Email),
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to update delivery: %w", op, err)
+	}
+
+	err = qtx.UpdatePayment(ctx, gen.UpdatePaymentParams{
+		OrderUid:      order.OrderUID,
+		TransactionID: order.Payment.Transaction,
+		RequestID:     tools.ToText(order.Payment.RequestID),
+		Currency:      tools.ToText(order.Payment.Currency),
+		Provider:      tools.ToText(order.Payment.Provider),
+		Amount:        tools.ToInt4(order.Payment.Amount),
+		PaymentDt:     tools.ToInt8(order.Payment.PaymentDt),
+		Bank:          tools.ToText(order.Payment.Bank),
+		DeliveryCost:  tools.ToInt4(order.Payment.DeliveryCost),
+		GoodsTotal:    tools.ToInt4(order.Payment.GoodsTotal),
+		CustomFee:     tools.ToInt4(order.Payment.CustomFee),
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to update payment: %w", op, err)
+	}
+
+	if err = qtx.DeleteItems(ctx, order.OrderUID); err != nil {
+		return false, fmt.Errorf("%s: failed to delete items: %w", op, err)
+	}
+
+	if err = insertItems(ctx, tx, order); err != nil {
+		return false, fmt.Errorf("%s: failed to insert items: %w", op, err)
+	}
+
+	if err = createOutboxEvent(ctx, qtx, model.EventOrderUpdated, order); err != nil {
+		return false, fmt.Errorf("%s: failed to create outbox event: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("%s: failed to commit tx: %w", op, err)
+	}
+
+	return true, nil
+}
+
+func insertItems(ctx context.Context, tx pgx.Tx, order *model.Order) error {
 	itemsRows := make([][]any, len(order.Items))
 	for i, item := range order.Items {
 		itemsRows[i] = []any{
@@ -186,47 +347,236 @@ func (r *Repository) CreateOrder(ctx context.Context, order *model.Order) error
 		"brand",
 		"status",
 	}
-	_, err = tx.CopyFrom(ctx,
+	_, err := tx.CopyFrom(ctx,
 		pgx.Identifier{"items"},
 		itemColumnNames,
 		pgx.CopyFromRows(itemsRows),
 	)
-	if err != nil {
-		return fmt.Errorf("%s: failed to insert items: %w", op, err)
+	return err
+}
+
+// CreateOrders stores a batch of orders in a single transaction using COPY.
+// The returned slice holds a per-order error aligned with the input: orders that
+// already exist are reported as ErrOrderAlreadyExists without failing the batch.
+// If the bulk insert is rejected, orders are stored one by one to isolate the offenders.
+// A non-nil error means the whole batch failed and nothing was stored.
+func (r *Repository) CreateOrders(ctx context.Context, orders []*model.Order) ([]error, error) {
+	const op = "repositories.order.CreateOrders"
+
+	errs := make([]error, len(orders))
+	if len(orders) == 0 {
+		return errs, nil
 	}
 
-	err = qtx.CreateDelivery(ctx, gen.CreateDeliveryParams{
-		OrderUid: order.OrderUID,
-		DelName:  order.Delivery.Name,
-		Phone:    order.Delivery.Phone,
-		Zip:      tools.ToText(order.Delivery.Zip),
-		City:     tools.ToText(order.Delivery.City),
-		Address:  tools.ToText(order.Delivery.Address),
-		Region:   tools.ToText(order.Delivery.Region),
-		Email:    tools.ToText(order.Delivery.Email),
-	})
+	orderUIDs := make([]string, len(orders))
+	for i, order := range orders {
+		orderUIDs[i] = order.OrderUID
+	}
+
+	existingUIDs, err := r.queries.GetExistingOrderUIDs(ctx, orderUIDs)
 	if err != nil {
-		return fmt.Errorf("%s: failed to create delivery: %w", op, err)
+		return nil, fmt.Errorf("%s: failed to get existing orders: %w", op, tools.MarkRetryable(err))
 	}
 
-	err = qtx.CreatePayment(ctx, gen.CreatePaymentParams{
-		OrderUid:      order.OrderUID,
-		TransactionID: order.Payment.Transaction,
-		RequestID:     tools.ToText(order.Payment.RequestID),
-		Currency:      tools.ToText(order.Payment.Currency),
-		Provider:      tools.ToText(order.Payment.Provider),
-		Amount:        tools.ToInt4(order.Payment.Amount),
-		PaymentDt:     tools.ToInt8(order.Payment.PaymentDt),
-		Bank:          tools.ToText(order.Payment.Bank),
-		DeliveryCost:  tools.ToInt4(order.Payment.DeliveryCost),
-		GoodsTotal:    tools.ToInt4(order.Payment.GoodsTotal),
-		CustomFee:     tools.ToInt4(order.Payment.CustomFee),
-	})
+	seen := make(map[string]struct{}, len(orders))
+	for _, orderUID := range existingUIDs {
+		seen[orderUID] = struct{}{}
+	}
+
+	pending := make([]int, 0, len(orders))
+	for i, order := range orders {
+		if _, exists := seen[order.OrderUID]; exists {
+			errs[i] = orderErrs.ErrOrderAlreadyExists
+			continue
+		}
+		seen[order.OrderUID] = struct{}{}
+		pending = append(pending, i)
+	}
+
+	if len(pending) == 0 {
+		return errs, nil
+	}
+
+	batch := make([]*model.Order, len(pending))
+	for i, idx := range pending {
+		batch[i] = orders[idx]
+	}
+
+	err = r.copyOrders(ctx, batch)
+	if err == nil {
+		return errs, nil
+	}
+	if !tools.IsUniqueErr(err) && tools.IsRetryableErr(err) {
+		return nil, fmt.Errorf("%s: %w", op, tools.MarkRetryable(err))
+	}
+
+	for _, idx := range pending {
+		errs[idx] = r.CreateOrder(ctx, orders[idx])
+	}
+
+	return errs, nil
+}
+
+func (r *Repository) copyOrders(ctx context.Context, orders []*model.Order) error {
+	const op = "repositories.order.copyOrders"
+
+	tx, err := r.executor.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("%s: failed to create payment: %w", op, err)
+		return fmt.Errorf("%s: failed to begin tx: %w", op, err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	return tx.Commit(ctx)
+	ordersRows := make([][]any, len(orders))
+	deliveriesRows := make([][]any, len(orders))
+	paymentsRows := make([][]any, len(orders))
+	itemsRows := make([][]any, 0, len(orders))
+	for i, order := range orders {
+		ordersRows[i] = []any{
+			order.OrderUID,
+			order.TrackNumber,
+			order.Entry,
+			order.Locale,
+			tools.ToText(order.InternalSignature),
+			order.CustomerID,
+			tools.ToText(order.DeliveryService),
+			tools.ToText(order.ShardKey),
+			order.SmID,
+			tools.ToTimestamp(order.DateCreated),
+			tools.ToText(order.OofShard),
+		}
+		deliveriesRows[i] = []any{
+			order.OrderUID,
+			order.Delivery.Name,
+			order.Delivery.Phone,
+			tools.ToText(order.Delivery.Zip),
+			tools.ToText(order.Delivery.City),
+			tools.ToText(order.Delivery.Address),
+			tools.ToText(order.Delivery.Region),
+			tools.ToText(order.Delivery.Email),
+		}
+		paymentsRows[i] = []any{
+			order.OrderUID,
+			order.Payment.Transaction,
+			tools.ToText(order.Payment.RequestID),
+			tools.ToText(order.Payment.Currency),
+			tools.ToText(order.Payment.Provider),
+			tools.ToInt4(order.Payment.Amount),
+			tools.ToInt8(order.Payment.PaymentDt),
+			tools.ToText(order.Payment.Bank),
+			tools.ToInt4(order.Payment.DeliveryCost),
+			tools.ToInt4(order.Payment.GoodsTotal),
+			tools.ToInt4(order.Payment.CustomFee),
+		}
+		for _, item := range order.Items {
+			itemsRows = append(itemsRows, []any{
+				order.OrderUID,
+				item.ChrtID,
+				item.TrackNumber,
+				item.Price,
+				item.RID,
+				item.Name,
+				item.Sale,
+				item.Size,
+				item.TotalPrice,
+				item.NmID,
+				item.Brand,
+				item.Status,
+			})
+		}
+	}
+
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{
+			table: "orders",
+			columns: []string{
+				"order_uid",
+				"track_number",
+				"entry",
+				"locale",
+				"internal_signature",
+				"customer_id",
+				"delivery_service",
+				"shardkey",
+				"sm_id",
+				"date_created",
+				"oof_shard",
+			},
+			rows: ordersRows,
+		},
+		{
+			table: "deliveries",
+			columns: []string{
+				"order_uid",
+				"del_name",
+				"phone",
+				"zip",
+				"city",
+				"address",
+				"region",
+				"email",
+			},
+			rows: deliveriesRows,
+		},
+		{
+			table: "payments",
+			columns: []string{
+				"order_uid",
+				"transaction_id",
+				"request_id",
+				"currency",
+				"provider",
+				"amount",
+				"payment_dt",
+				"bank",
+				"delivery_cost",
+				"goods_total",
+				"custom_fee",
+			},
+			rows: paymentsRows,
+		},
+		{
+			table: "items",
+			columns: []string{
+				"order_uid",
+				"chrt_id",
+				"track_number",
+				"price",
+				"rid",
+				"item_name",
+				"sale",
+				"item_size",
+				"total_price",
+				"nm_id",
+				"brand",
+				"status",
+			},
+			rows: itemsRows,
+		},
+	}
+
+	for _, c := range copies {
+		if _, err = tx.CopyFrom(ctx,
+			pgx.Identifier{c.table},
+			c.columns,
+			pgx.CopyFromRows(c.rows),
+		); err != nil {
+			return fmt.Errorf("%s: failed to copy %s: %w", op, c.table, err)
+		}
+	}
+
+	if err = copyOutboxEvents(ctx, tx, model.EventOrderCreated, orders); err != nil {
+		return fmt.Errorf("%s: failed to copy outbox events: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: failed to commit tx: %w", op, err)
+	}
+
+	return nil
 }
 
 func (r *Repository) GetOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
@@ -249,6 +599,153 @@ func (r *Repository) GetOrdersForCache(ctx context.Context, limit int) ([]*model
 		return []*model.Order{}, nil
 	}
 
+	orders, err := hydrateOrders(ctx, qtx, ordersDB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit tx: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) ListOrders(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error) {
+	const op = "repositories.order.ListOrders"
+
+	tx, err := r.executor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := r.queries.WithTx(tx)
+
+	params := gen.ListOrdersDescParams{
+		CustomerID:      tools.ToText(filter.CustomerID),
+		TrackNumber:     tools.ToText(filter.TrackNumber),
+		DeliveryService: tools.ToText(filter.DeliveryService),
+		Locale:          tools.ToText(filter.Locale),
+		Currency:        tools.ToText(filter.Currency),
+		Provider:        tools.ToText(filter.Provider),
+		Bank:            tools.ToText(filter.Bank),
+		CreatedFrom:     tools.ToTimestamp(filter.CreatedFrom),
+		CreatedTo:       tools.ToTimestamp(filter.CreatedTo),
+		PageSize:        int32(filter.Limit),
+	}
+	if filter.Cursor != nil {
+		params.CursorDate = tools.ToTimestamp(filter.Cursor.DateCreated)
+		params.CursorUid = tools.ToText(filter.Cursor.OrderUID)
+	}
+
+	var ordersDB []gen.Order
+	if filter.Ascending {
+		ordersDB, err = qtx.ListOrdersAsc(ctx, gen.ListOrdersAscParams(params))
+	} else {
+		ordersDB, err = qtx.ListOrdersDesc(ctx, params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list orders: %w", op, err)
+	}
+
+	if len(ordersDB) == 0 {
+		return []*model.Order{}, nil
+	}
+
+	orders, err := hydrateOrders(ctx, qtx, ordersDB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit tx: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) GetOrdersByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	const op = "repositories.order.GetOrdersByTrackNumber"
+
+	return r.getOrdersBy(ctx, op, func(qtx *gen.Queries) ([]gen.Order, error) {
+		return qtx.GetOrdersByTrackNumber(ctx, trackNumber)
+	})
+}
+
+func (r *Repository) GetOrdersByTransactionID(ctx context.Context, transactionID string) ([]*model.Order, error) {
+	const op = "repositories.order.GetOrdersByTransactionID"
+
+	return r.getOrdersBy(ctx, op, func(qtx *gen.Queries) ([]gen.Order, error) {
+		return qtx.GetOrdersByTransactionID(ctx, transactionID)
+	})
+}
+
+func (r *Repository) getOrdersBy(
+	ctx context.Context,
+	op string,
+	query func(qtx *gen.Queries) ([]gen.Order, error),
+) ([]*model.Order, error) {
+	tx, err := r.executor.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := r.queries.WithTx(tx)
+
+	ordersDB, err := query(qtx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get orders: %w", op, err)
+	}
+
+	if len(ordersDB) == 0 {
+		return nil, orderErrs.ErrOrderNotFount
+	}
+
+	orders, err := hydrateOrders(ctx, qtx, ordersDB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit tx: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) GetCustomerSummary(ctx context.Context, customerID string) (*model.CustomerSummary, error) {
+	const op = "repositories.order.GetCustomerSummary"
+
+	rows, err := r.queries.GetCustomerOrderSummary(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get summary: %w", op, err)
+	}
+
+	summary := &model.CustomerSummary{
+		CustomerID:      customerID,
+		SpendByCurrency: make(map[string]int64, len(rows)),
+	}
+	for _, row := range rows {
+		summary.OrderCount += row.OrderCount
+		summary.SpendByCurrency[row.Currency.String] += row.TotalAmount
+
+		if row.FirstOrderAt.Valid &&
+			(summary.FirstOrderAt.IsZero() || row.FirstOrderAt.Time.Before(summary.FirstOrderAt)) {
+			summary.FirstOrderAt = row.FirstOrderAt.Time
+		}
+		if row.LastOrderAt.Valid && row.LastOrderAt.Time.After(summary.LastOrderAt) {
+			summary.LastOrderAt = row.LastOrderAt.Time
+		}
+	}
+
+	return summary, nil
+}
+
+// hydrateOrders loads items, deliveries and payments for ordersDB in bulk and
+// assembles the aggregates, keeping the order of ordersDB.
+func hydrateOrders(ctx context.Context, qtx *gen.Queries, ordersDB []gen.Order) ([]*model.Order, error) {
 	orderIDs := make([]string, len(ordersDB))
 	for i, order := range ordersDB {
 		orderIDs[i] = order.OrderUid
@@ -256,19 +753,30 @@ func (r *Repository) GetOrdersForCache(ctx context.Context, limit int) ([]*model
 
 	items, err := qtx.GetItemsForOrders(ctx, orderIDs)
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get items: %w", op, err)
+		return nil, fmt.Errorf("failed to get items: %w", err)
 	}
 
 	deliveries, err := qtx.GetDeliveriesForOrders(ctx, orderIDs)
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get deliveries: %w", op, err)
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
 	}
 
 	payments, err := qtx.GetPaymentsForOrders(ctx, orderIDs)
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get payments: %w", op, err)
+		return nil, fmt.Errorf("failed to get payments: %w", err)
 	}
 
+	return assembleOrders(ordersDB, items, deliveries, payments)
+}
+
+// assembleOrders joins the order rows with their items, deliveries and payments,
+// keeping the order of ordersDB.
+func assembleOrders(
+	ordersDB []gen.Order,
+	items []gen.Item,
+	deliveries []gen.Delivery,
+	payments []gen.Payment,
+) ([]*model.Order, error) {
 	itemsMap := make(map[string][]gen.Item)
 	for _, item := range items {
 		itemsMap[item.OrderUid] = append(itemsMap[item.OrderUid], item)
@@ -291,10 +799,14 @@ func (r *Repository) GetOrdersForCache(ctx context.Context, limit int) ([]*model
 
 		delivery, deliveryExists := deliveriesMap[orderUID]
 		payment, paymentExists := paymentsMap[orderUID]
-		orderItems, itemsExist := itemsMap[orderUID]
-
-		if !deliveryExists || !paymentExists || !itemsExist {
-			continue
+		orderItems := itemsMap[orderUID]
+
+		// Orders are written in a single transaction, so a missing delivery or payment
+		// means the row is broken. Skipping it would shorten the page the caller
+		// paginates on. An order without items is valid and reads back with none.
+		if !deliveryExists || !paymentExists {
+			return nil, fmt.Errorf("order %s is incomplete: delivery %t, payment %t",
+				orderUID, deliveryExists, paymentExists)
 		}
 
 		modelItems := make([]model.Item, len(orderItems))
@@ -357,9 +869,5 @@ func (r *Repository) GetOrdersForCache(ctx context.Context, limit int) ([]*model
 		orders = append(orders, order)
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("%s: failed to commit tx: %w", op, err)
-	}
-
 	return orders, nil
 }
@@ -0,0 +1,55 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/repositories/order/gen"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestAssembleOrders(t *testing.T) {
+	ordersDB := []gen.Order{{OrderUid: "a"}, {OrderUid: "b"}}
+	deliveries := []gen.Delivery{{OrderUid: "a"}, {OrderUid: "b"}}
+	payments := []gen.Payment{{OrderUid: "a"}, {OrderUid: "b"}}
+	items := []gen.Item{
+		{OrderUid: "a", ChrtID: pgtype.Int8{Int64: 1, Valid: true}},
+		{OrderUid: "a", ChrtID: pgtype.Int8{Int64: 2, Valid: true}},
+	}
+
+	tests := []struct {
+		name       string
+		deliveries []gen.Delivery
+		payments   []gen.Payment
+		wantErr    bool
+	}{
+		{name: "complete", deliveries: deliveries, payments: payments},
+		{name: "missing delivery", deliveries: deliveries[:1], payments: payments, wantErr: true},
+		{name: "missing payment", deliveries: deliveries, payments: payments[:1], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, err := assembleOrders(ordersDB, items, tt.deliveries, tt.payments)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error for an incomplete order")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("assembleOrders: %v", err)
+			}
+
+			if len(orders) != 2 || orders[0].OrderUID != "a" || orders[1].OrderUID != "b" {
+				t.Fatalf("got %d orders, want a and b in order", len(orders))
+			}
+			if len(orders[0].Items) != 2 {
+				t.Fatalf("order a has %d items, want 2", len(orders[0].Items))
+			}
+			if orders[1].Items == nil || len(orders[1].Items) != 0 {
+				t.Fatalf("order b items = %#v, want an empty slice", orders[1].Items)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/D1sordxr/wb-tech-l0/internal/infrastructure/storage/postgres/repositories/order")
+
+func startSpan(ctx context.Context, name, orderUID string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attribute.String("order.uid", orderUID)))
+}
+
+// endSpan marks the span as failed, except for a missing or already stored order,
+// which callers handle as regular outcomes.
+func endSpan(span trace.Span, err error) {
+	if err == nil || errors.Is(err, orderErrs.ErrOrderNotFount) || errors.Is(err, orderErrs.ErrOrderAlreadyExists) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -1,7 +1,13 @@
 package tools
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -13,3 +19,42 @@ func IsUniqueErr(err error) bool {
 	}
 	return false
 }
+
+// IsRetryableErr reports whether err is a transient failure (lost connection,
+// serialization conflict, server shutting down) that may succeed on retry. A cancelled
+// or expired caller context is not: it also reports a timeout, but retrying cannot help.
+func IsRetryableErr(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", "40P01", "53300", "57P01", "57P02", "57P03":
+			return true
+		}
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	if pgconn.SafeToRetry(err) || pgconn.Timeout(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// MarkRetryable wraps transient failures with ErrStorageUnavailable, so callers outside
+// the storage layer can tell them apart without knowing about Postgres.
+func MarkRetryable(err error) error {
+	if err == nil || errors.Is(err, orderErrs.ErrStorageUnavailable) || !IsRetryableErr(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", orderErrs.ErrStorageUnavailable, err)
+}
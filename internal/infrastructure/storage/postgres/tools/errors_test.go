@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsRetryableErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "connection exception", err: &pgconn.PgError{Code: "08006"}, want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "network error", err: &net.OpError{Op: "read", Err: errors.New("connection reset")}, want: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: false},
+		{name: "wrapped deadline exceeded", err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableErr(tt.err); got != tt.want {
+				t.Fatalf("IsRetryableErr(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkRetryableLeavesDeadlineAlone(t *testing.T) {
+	if err := MarkRetryable(context.DeadlineExceeded); errors.Is(err, orderErrs.ErrStorageUnavailable) {
+		t.Fatal("a caller deadline was marked as storage unavailable")
+	}
+}
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentation = "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+
+// headerCarrier lets the propagator read and write trace context in Kafka message headers.
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, header := range *c.headers {
+		if header.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, header := range *c.headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
+
+// Inject writes the trace context of ctx into the message headers.
+func Inject(ctx context.Context, message *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{headers: &message.Headers})
+}
+
+// Extract returns ctx carrying the trace context found in the message headers.
+func Extract(ctx context.Context, message kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier{headers: &message.Headers})
+}
+
+// StartPublish starts a producer span for messages about to be written to topic.
+// The returned context is the one to inject into them.
+func StartPublish(ctx context.Context, topic string, count int, links ...trace.Link) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, "send "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingBatchMessageCount(count),
+		),
+	)
+}
+
+// StartProcess starts a consumer span for a fetched message, continuing the trace
+// of the producer that wrote it.
+func StartProcess(ctx context.Context, message kafka.Message) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(Extract(ctx, message), "process "+message.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(processAttributes(message)...),
+	)
+}
+
+// StartProcessBatch starts a consumer span for messages processed together. It links
+// to the trace of every message, since a span can only have one parent.
+func StartProcessBatch(ctx context.Context, topic string, messages []kafka.Message) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(messages))
+	for _, message := range messages {
+		spanContext := trace.SpanContextFromContext(Extract(ctx, message))
+		if spanContext.IsValid() {
+			links = append(links, trace.Link{SpanContext: spanContext, Attributes: processAttributes(message)})
+		}
+	}
+
+	return otel.Tracer(instrumentation).Start(ctx, "process "+topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingBatchMessageCount(len(messages)),
+		),
+	)
+}
+
+func processAttributes(message kafka.Message) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemKafka,
+		semconv.MessagingOperationTypeProcess,
+		semconv.MessagingDestinationName(message.Topic),
+		semconv.MessagingDestinationPartitionID(strconv.Itoa(message.Partition)),
+		semconv.MessagingKafkaOffset(int(message.Offset)),
+		semconv.MessagingKafkaMessageKey(string(message.Key)),
+	}
+}
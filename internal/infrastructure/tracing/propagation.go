@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceParentKey = "traceparent"
+
+// TraceParent returns the W3C traceparent of the span in ctx, or an empty string
+// when there is none. It is what gets stored with data published later on.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get(traceParentKey)
+}
+
+// LinkTraceParent returns a link to the span described by traceParent. It reports
+// false when traceParent is empty or malformed.
+func LinkTraceParent(traceParent string) (trace.Link, bool) {
+	if traceParent == "" {
+		return trace.Link{}, false
+	}
+
+	carrier := propagation.MapCarrier{traceParentKey: traceParent}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return trace.Link{}, false
+	}
+
+	return trace.Link{SpanContext: spanContext}, true
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Provider installs the global tracer provider and propagator and flushes
+// buffered spans on shutdown. When tracing is disabled, spans are not recorded,
+// but trace context received from upstream is still passed on.
+type Provider struct {
+	log      appPorts.Logger
+	provider *sdktrace.TracerProvider
+	output   io.Closer
+}
+
+func NewProvider(log appPorts.Logger, cfg *config.Tracing) *Provider {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	p := &Provider{log: log}
+	if !cfg.Enabled {
+		return p
+	}
+
+	exporter, err := p.newExporter(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	p.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(p.provider)
+
+	return p
+}
+
+func (p *Provider) newExporter(cfg *config.Tracing) (sdktrace.SpanExporter, error) {
+	const op = "tracing.Provider.newExporter"
+
+	if cfg.Exporter == "stdout" {
+		var output io.Writer = os.Stdout
+		if cfg.File != "" {
+			file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			p.output = file
+			output = file
+		}
+		return stdouttrace.New(stdouttrace.WithWriter(output))
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	// The client connects lazily, so an unreachable collector does not block start-up.
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+func (p *Provider) Run(ctx context.Context) error {
+	if p.provider != nil {
+		p.log.Info("Tracing enabled")
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// Shutdown exports the spans still buffered; it runs late in the shutdown
+// sequence so spans of the components stopping before it are included.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	const op = "tracing.Provider.Shutdown"
+
+	if p.provider == nil {
+		return nil
+	}
+
+	err := p.provider.Shutdown(ctx)
+	if p.output != nil {
+		_ = p.output.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
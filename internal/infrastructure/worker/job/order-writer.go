@@ -3,53 +3,172 @@ package job
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
 
 	kafkaLib "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// MockOrderWriter is a load generator: its producers write generated orders into the
+// orders topic at the configured rate until the count or duration is reached, then
+// it reports the throughput it achieved.
 type MockOrderWriter struct {
-	log      appPorts.Logger
-	orderGen *mock.Generator
-	writer   *kafka.Writer
+	log       appPorts.Logger
+	orderGen  *mock.Generator
+	writer    *kafka.Writer
+	rate      float64
+	burst     int
+	count     int64
+	duration  time.Duration
+	producers int
 }
 
 func NewMockOrderWriter(
 	log appPorts.Logger,
 	writer *kafka.Writer,
+	cfg *config.LoadGenerator,
 ) *MockOrderWriter {
 	return &MockOrderWriter{
-		log:      log,
-		orderGen: mock.NewMockGenerator(),
-		writer:   writer,
+		log:       log,
+		orderGen:  mock.NewGenerator(&cfg.Profile),
+		writer:    writer,
+		rate:      cfg.Rate,
+		burst:     cfg.Burst,
+		count:     int64(cfg.Count),
+		duration:  cfg.Duration,
+		producers: cfg.Producers,
 	}
 }
 
+type loadStats struct {
+	issued  atomic.Int64
+	sent    atomic.Int64
+	failed  atomic.Int64
+	mu      sync.Mutex
+	lastErr error
+}
+
+func (s *loadStats) fail(err error) {
+	s.failed.Add(1)
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+}
+
 func (w *MockOrderWriter) Start(ctx context.Context) error {
 	const op = "job.MockOrderWriter.Start"
 
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	if w.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.duration)
+		defer cancel()
+	}
+
+	w.log.Info("Starting load generator",
+		"op", op,
+		"topic", w.writer.GetTopic(),
+		"rate", w.rate,
+		"burst", w.burst,
+		"count", w.count,
+		"duration", w.duration,
+		"producers", w.producers,
+	)
+
+	limiter := newTokenBucket(w.rate, w.burst)
+	stats := &loadStats{}
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	for range w.producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.produce(ctx, limiter, stats)
+		}()
+	}
+	wg.Wait()
+
+	w.report(started, stats)
+	return nil
+}
 
+func (w *MockOrderWriter) produce(ctx context.Context, limiter *tokenBucket, stats *loadStats) {
 	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			order := w.orderGen.GenerateOrder()
-			data, _ := json.Marshal(order)
-			if err := w.writer.WriteMessages(ctx, kafkaLib.Message{
-				Topic: w.writer.GetTopic(),
-				Value: data,
-			}); err != nil {
-				w.log.Error("failed to write mock order", "op", op, "error", err.Error())
+		if w.count > 0 && stats.issued.Add(1) > w.count {
+			return
+		}
+		if !limiter.wait(ctx) {
+			return
+		}
+
+		if err := w.write(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
 			}
+			stats.fail(err)
+			continue
 		}
+		stats.sent.Add(1)
+	}
+}
+
+func (w *MockOrderWriter) write(ctx context.Context) error {
+	const op = "job.MockOrderWriter.write"
+
+	data, err := json.Marshal(w.orderGen.GenerateOrder())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	message := kafkaLib.Message{
+		Topic: w.writer.GetTopic(),
+		Value: data,
+	}
+
+	spanCtx, span := tracing.StartPublish(ctx, message.Topic, 1)
+	defer span.End()
+	tracing.Inject(spanCtx, &message)
+
+	if err = w.writer.WriteMessages(spanCtx, message); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (w *MockOrderWriter) report(started time.Time, stats *loadStats) {
+	const op = "job.MockOrderWriter.report"
+
+	elapsed := time.Since(started)
+	sent, failed := stats.sent.Load(), stats.failed.Load()
+
+	fields := []any{
+		"op", op,
+		"sent", sent,
+		"failed", failed,
+		"elapsed", elapsed.Round(time.Millisecond).String(),
+		"throughput_per_sec", float64(sent) / max(elapsed.Seconds(), 1e-9),
+		"target_rate", w.rate,
+		"producers", w.producers,
 	}
+	if failed == 0 {
+		w.log.Info("Load generator finished", fields...)
+		return
+	}
+
+	stats.mu.Lock()
+	lastErr := stats.lastErr
+	stats.mu.Unlock()
+	w.log.Warn("Load generator finished with errors", append(fields, "last_error", lastErr.Error())...)
 }
 
 func (w *MockOrderWriter) Stop(_ context.Context) error {
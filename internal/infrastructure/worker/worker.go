@@ -30,30 +30,48 @@ func NewWorker(
 	}
 }
 
+// Run returns the first handler error once all handlers have stopped, so the
+// supervisor sees the failure. Handlers that finish their work without an error
+// leave the worker idle until ctx is done.
 func (w *Worker) Run(ctx context.Context) error {
 	w.log.Info("starting worker", "total_handlers", len(w.handlers))
 
-	errChan := make(chan error, 1)
-	errGroup, ctx := errgroup.WithContext(ctx)
-	go func() {
-		errChan <- errGroup.Wait()
-	}()
-
+	errGroup, groupCtx := errgroup.WithContext(ctx)
 	for _, handler := range w.handlers {
-		func(handler Handlers) {
-			errGroup.Go(func() error {
-				return handler.Start(ctx)
-			})
-		}(handler)
+		errGroup.Go(func() error {
+			return handler.Start(groupCtx)
+		})
 	}
 
-	select {
-	case err := <-errChan:
+	if err := errGroup.Wait(); err != nil && ctx.Err() == nil {
 		w.log.Error("worker received critical error, initiating shutdown", "error", err.Error())
 		return fmt.Errorf("worker error: %w", err)
-	case <-ctx.Done():
-		return nil
 	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// HealthCheck reports the worst health of the handlers that implement ports.HealthChecker.
+func (w *Worker) HealthCheck(ctx context.Context) ports.Health {
+	health := ports.Up()
+	for _, handler := range w.handlers {
+		checker, ok := handler.(ports.HealthChecker)
+		if !ok {
+			continue
+		}
+
+		handlerHealth := checker.HealthCheck(ctx)
+		if handlerHealth.Ready() {
+			continue
+		}
+		handlerHealth.Detail = fmt.Sprintf("%T: %s", handler, handlerHealth.Detail)
+		if !handlerHealth.Live() {
+			return handlerHealth
+		}
+		health = handlerHealth
+	}
+	return health
 }
 
 func (w *Worker) Shutdown(ctx context.Context) error {
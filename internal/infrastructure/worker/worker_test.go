@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+var errBoom = errors.New("boom")
+
+// handler returns err from Start, or waits for ctx when block is set.
+type handler struct {
+	err   error
+	block bool
+}
+
+func (h handler) Start(ctx context.Context) error {
+	if h.block {
+		<-ctx.Done()
+	}
+	return h.err
+}
+
+func (h handler) Stop(context.Context) error {
+	return nil
+}
+
+func newTestWorker(handlers ...Handlers) *Worker {
+	return NewWorker(slog.New(slog.NewTextHandler(io.Discard, nil)), handlers...)
+}
+
+func TestRunReturnsHandlerError(t *testing.T) {
+	w := newTestWorker(handler{block: true}, handler{err: errBoom})
+
+	done := make(chan error, 1)
+	go func() { done <- w.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("Run = %v, want %v", err, errBoom)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after a handler failed")
+	}
+}
+
+func TestRunIdlesUntilContextIsDone(t *testing.T) {
+	w := newTestWorker(handler{}, handler{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Run returned %v before ctx was done", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run = %v, want nil", err)
+	}
+}
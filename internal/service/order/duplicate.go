@@ -0,0 +1,137 @@
+package order
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+type ConflictPolicy string
+
+const (
+	ConflictReject        ConflictPolicy = "reject"
+	ConflictLastWriteWins ConflictPolicy = "last_write_wins"
+	ConflictNewerWins     ConflictPolicy = "newer_wins"
+)
+
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(value); policy {
+	case ConflictReject, ConflictLastWriteWins, ConflictNewerWins:
+		return policy, nil
+	case "":
+		return ConflictReject, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q", value)
+	}
+}
+
+type ingestionCounters struct {
+	created    atomic.Int64
+	duplicates atomic.Int64
+	updated    atomic.Int64
+	stale      atomic.Int64
+	conflicts  atomic.Int64
+}
+
+func (c *ingestionCounters) snapshot() model.IngestionStats {
+	return model.IngestionStats{
+		Created:    c.created.Load(),
+		Duplicates: c.duplicates.Load(),
+		Updated:    c.updated.Load(),
+		Stale:      c.stale.Load(),
+		Conflicts:  c.conflicts.Load(),
+	}
+}
+
+// resolveDuplicate decides what to do with an order whose UID is already stored.
+// An identical redelivery is acknowledged as a no-op; a different payload is handled
+// according to the configured conflict policy.
+func (uc *UseCase) resolveDuplicate(ctx context.Context, orderModel *model.Order) error {
+	const op = "service.order.UseCase.resolveDuplicate"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "orderID", orderModel.OrderUID, "policy", uc.conflictPolicy}, args...)
+	}
+
+	stored, err := uc.repo.GetOrder(ctx, orderModel.OrderUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if sameOrder(stored, orderModel) {
+		uc.stats.duplicates.Add(1)
+		uc.log.InfoContext(ctx, "Duplicate order acknowledged", withFields()...)
+		uc.cache.Set(stored.OrderUID, stored)
+		return nil
+	}
+
+	if uc.conflictPolicy == ConflictReject {
+		uc.stats.conflicts.Add(1)
+		uc.log.WarnContext(ctx, "Conflicting order rejected", withFields()...)
+		return fmt.Errorf("%s: %w", op, orderErrs.ErrOrderConflict)
+	}
+
+	updated, err := uc.repo.UpdateOrder(ctx, orderModel, uc.conflictPolicy == ConflictNewerWins)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !updated {
+		if uc.conflictPolicy == ConflictNewerWins {
+			uc.stats.stale.Add(1)
+			uc.log.InfoContext(ctx, "Stale order ignored", withFields()...)
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, orderErrs.ErrOrderNotFount)
+	}
+
+	uc.stats.updated.Add(1)
+	uc.log.InfoContext(ctx, "Order updated", withFields()...)
+	uc.cache.Set(orderModel.OrderUID, orderModel)
+	uc.invalidateIndexes(stored)
+	uc.invalidateIndexes(orderModel)
+	uc.broadcastInvalidation(ctx, model.InvalidationRefresh, orderModel.OrderUID)
+
+	return nil
+}
+
+func isDuplicate(err error) bool {
+	return errors.Is(err, orderErrs.ErrOrderAlreadyExists)
+}
+
+// sameOrder compares orders the way they round-trip through storage:
+// timestamps lose their zone and sub-microsecond part, items have no stable order.
+func sameOrder(stored, incoming *model.Order) bool {
+	return reflect.DeepEqual(normalizeOrder(stored), normalizeOrder(incoming))
+}
+
+func normalizeOrder(order *model.Order) model.Order {
+	normalized := *order
+
+	created := order.DateCreated
+	normalized.DateCreated = time.Date(
+		created.Year(), created.Month(), created.Day(),
+		created.Hour(), created.Minute(), created.Second(), created.Nanosecond(),
+		time.UTC,
+	).Truncate(time.Microsecond)
+
+	normalized.Items = slices.Clone(order.Items)
+	slices.SortFunc(normalized.Items, func(a, b model.Item) int {
+		return cmp.Or(
+			cmp.Compare(a.ChrtID, b.ChrtID),
+			cmp.Compare(a.RID, b.RID),
+			cmp.Compare(a.NmID, b.NmID),
+		)
+	})
+	if normalized.Items == nil {
+		normalized.Items = []model.Item{}
+	}
+
+	return normalized
+}
@@ -0,0 +1,82 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+// ApplyInvalidation brings the locally cached copy of an order up to date after it
+// changed on another replica. If the order no longer exists or cannot be reloaded,
+// it is dropped from the cache.
+func (uc *UseCase) ApplyInvalidation(ctx context.Context, invalidation model.CacheInvalidation) error {
+	const op = "service.order.UseCase.ApplyInvalidation"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "orderID", invalidation.OrderUID, "action", invalidation.Action}, args...)
+	}
+
+	// The cached copy may be listed under index keys the changed order no longer has.
+	// Peek finds it even if expired and does not count the lookup as a read.
+	if cached := uc.cache.Peek(invalidation.OrderUID); cached != nil {
+		uc.invalidateIndexes(cached)
+	}
+
+	switch invalidation.Action {
+	case model.InvalidationEvict:
+		uc.cache.Delete(invalidation.OrderUID)
+	case model.InvalidationRefresh:
+		// A load started before the change may still return the old copy, so don't join it.
+		uc.loads.Forget(invalidation.OrderUID)
+
+		orderModel, err := uc.loadOrder(ctx, invalidation.OrderUID)
+		if err != nil {
+			// Whatever went wrong, the cached copy is out of date.
+			uc.cache.Delete(invalidation.OrderUID)
+			if !errors.Is(err, orderErrs.ErrOrderNotFount) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			break
+		}
+		uc.invalidateIndexes(orderModel)
+	case model.InvalidationCreated:
+		// Only a negative entry can be out of date. A cached copy comes from a cache
+		// shared with the creating replica and is already current.
+		if uc.cache.IsMissing(invalidation.OrderUID) {
+			uc.cache.Delete(invalidation.OrderUID)
+		}
+	default:
+		return fmt.Errorf("%s: unknown action %q", op, invalidation.Action)
+	}
+
+	uc.log.InfoContext(ctx, "Cache invalidation applied", withFields()...)
+
+	return nil
+}
+
+// broadcastInvalidation tells the other replicas that their copies of the orders are out
+// of date. The change is already stored, so a failure only delays them until the entry
+// expires.
+func (uc *UseCase) broadcastInvalidation(ctx context.Context, action model.InvalidationAction, orderUIDs ...string) {
+	const op = "service.order.UseCase.broadcastInvalidation"
+
+	if len(orderUIDs) == 0 {
+		return
+	}
+
+	invalidations := make([]model.CacheInvalidation, len(orderUIDs))
+	for i, orderUID := range orderUIDs {
+		invalidations[i] = model.CacheInvalidation{OrderUID: orderUID, Action: action}
+	}
+
+	if err := uc.invalidator.Invalidate(ctx, invalidations...); err != nil {
+		uc.log.WarnContext(ctx, "Failed to broadcast cache invalidation",
+			"op", op,
+			"orderIDs", orderUIDs,
+			"action", action,
+			"error", err.Error(),
+		)
+	}
+}
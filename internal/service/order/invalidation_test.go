@@ -0,0 +1,168 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	memoryCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+)
+
+// storage keeps orders in a map shared by every replica of a test.
+type storage struct {
+	ports.OrderRepo
+	mu     sync.Mutex
+	orders map[string]*model.Order
+}
+
+func newStorage() *storage {
+	return &storage{orders: make(map[string]*model.Order)}
+}
+
+func (s *storage) GetOrder(_ context.Context, orderID string) (*model.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return nil, orderErrs.ErrOrderNotFount
+	}
+	return order, nil
+}
+
+func (s *storage) CreateOrder(ctx context.Context, order *model.Order) error {
+	errs, _ := s.CreateOrders(ctx, []*model.Order{order})
+	return errs[0]
+}
+
+func (s *storage) CreateOrders(_ context.Context, orders []*model.Order) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	errs := make([]error, len(orders))
+	for i, order := range orders {
+		if order.TrackNumber == "" {
+			errs[i] = fmt.Errorf("order %s: missing track number", order.OrderUID)
+			continue
+		}
+		s.orders[order.OrderUID] = order
+	}
+	return errs, nil
+}
+
+// broadcast records every published invalidation.
+type broadcast struct {
+	mu            sync.Mutex
+	invalidations [][]model.CacheInvalidation
+}
+
+func (b *broadcast) Invalidate(_ context.Context, invalidations ...model.CacheInvalidation) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.invalidations = append(b.invalidations, invalidations)
+	return nil
+}
+
+type noopInitializer struct{}
+
+func (noopInitializer) GetOrdersForCache(context.Context, int) ([]*model.Order, error) {
+	return nil, nil
+}
+
+func (noopInitializer) GetMostReadOrdersForCache(context.Context, int) ([]*model.Order, error) {
+	return nil, nil
+}
+
+func (noopInitializer) RecordOrderReads(context.Context, map[string]int64) error {
+	return nil
+}
+
+// newReplica returns a use case with its own local cache over the shared storage.
+func newReplica(repo ports.OrderRepo, invalidator ports.CacheInvalidator) *UseCase {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cache := memoryCache.NewCache(log, &config.Cache{
+		TTL:         time.Minute,
+		NegativeTTL: time.Minute,
+		Shards:      1,
+		Eviction:    config.CacheEviction{Policy: string(memoryCache.EvictionLRU), MaxEntries: 100},
+	}, noopInitializer{})
+	return NewUseCase(log, repo, cache, invalidator, ConflictReject)
+}
+
+func generateOrders(count int) []dto.Order {
+	generator := mock.NewMockGenerator()
+	orders := make([]dto.Order, count)
+	for i := range orders {
+		orders[i] = generator.GenerateOrder()
+	}
+	return orders
+}
+
+func TestCreateBroadcastsCreatedOrders(t *testing.T) {
+	invalidator := &broadcast{}
+	uc := newReplica(newStorage(), invalidator)
+	orders := generateOrders(4)
+	orders[3].TrackNumber = ""
+
+	if err := uc.CreateOrder(context.Background(), orders[0]); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if _, err := uc.CreateOrders(context.Background(), orders[1:]); err != nil {
+		t.Fatalf("CreateOrders: %v", err)
+	}
+
+	want := [][]model.CacheInvalidation{
+		{{OrderUID: orders[0].ID, Action: model.InvalidationCreated}},
+		{
+			{OrderUID: orders[1].ID, Action: model.InvalidationCreated},
+			{OrderUID: orders[2].ID, Action: model.InvalidationCreated},
+		},
+	}
+	if fmt.Sprint(invalidator.invalidations) != fmt.Sprint(want) {
+		t.Fatalf("broadcast %v, want %v", invalidator.invalidations, want)
+	}
+}
+
+func TestCreatedOrderIsFoundOnOtherReplicas(t *testing.T) {
+	ctx := context.Background()
+	repo := newStorage()
+	invalidator := &broadcast{}
+	writer := newReplica(repo, invalidator)
+	reader := newReplica(repo, &broadcast{})
+	order := generateOrders(1)[0]
+
+	// The reader looks the order up before it exists and caches it as missing.
+	if _, err := reader.GetByID(ctx, order.ID); err == nil {
+		t.Fatal("GetByID found an order that was not created yet")
+	}
+
+	if err := writer.CreateOrder(ctx, order); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	for _, batch := range invalidator.invalidations {
+		for _, invalidation := range batch {
+			if err := reader.ApplyInvalidation(ctx, invalidation); err != nil {
+				t.Fatalf("ApplyInvalidation: %v", err)
+			}
+		}
+	}
+
+	got, err := reader.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetByID after the created invalidation: %v", err)
+	}
+	if got.OrderUID != order.ID {
+		t.Fatalf("GetByID = %s, want %s", got.OrderUID, order.ID)
+	}
+}
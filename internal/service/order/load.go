@@ -0,0 +1,62 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const loadTimeout = 5 * time.Second
+
+// loadOrder reads an order from the repository and caches it. Concurrent loads of
+// the same UID share one repository call; it runs detached from the callers' contexts
+// so one caller giving up does not fail the others.
+func (uc *UseCase) loadOrder(ctx context.Context, orderID string) (*model.Order, error) {
+	result := uc.startLoad(ctx, orderID)
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*model.Order), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refreshOrder reloads a stale order in the background.
+func (uc *UseCase) refreshOrder(ctx context.Context, orderID string) {
+	const op = "service.order.UseCase.refreshOrder"
+
+	result := uc.startLoad(ctx, orderID)
+	go func() {
+		if res := <-result; res.Err != nil {
+			uc.log.WarnContext(ctx, "Failed to refresh stale order", "op", op, "orderUID", orderID, "error", res.Err.Error())
+		}
+	}()
+}
+
+func (uc *UseCase) startLoad(ctx context.Context, orderID string) <-chan singleflight.Result {
+	return uc.loads.DoChan(orderID, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		orderModel, err := uc.repo.GetOrder(loadCtx, orderID)
+		if err != nil {
+			if errors.Is(err, orderErrs.ErrOrderNotFount) {
+				uc.cache.SetMissing(orderID)
+			}
+			return nil, err
+		}
+
+		uc.cache.Set(orderModel.OrderUID, orderModel)
+
+		return orderModel, nil
+	})
+}
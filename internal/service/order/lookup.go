@@ -0,0 +1,74 @@
+package order
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+const (
+	trackIndexPrefix       = "track:"
+	transactionIndexPrefix = "transaction:"
+)
+
+func (uc *UseCase) GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	const op = "service.order.UseCase.GetByTrackNumber"
+
+	return uc.lookup(ctx, op, trackIndexPrefix+trackNumber, func(ctx context.Context) ([]*model.Order, error) {
+		return uc.repo.GetOrdersByTrackNumber(ctx, trackNumber)
+	})
+}
+
+func (uc *UseCase) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Order, error) {
+	const op = "service.order.UseCase.GetByTransactionID"
+
+	return uc.lookup(ctx, op, transactionIndexPrefix+transactionID, func(ctx context.Context) ([]*model.Order, error) {
+		return uc.repo.GetOrdersByTransactionID(ctx, transactionID)
+	})
+}
+
+func (uc *UseCase) lookup(
+	ctx context.Context,
+	op string,
+	key string,
+	load func(ctx context.Context) ([]*model.Order, error),
+) ([]*model.Order, error) {
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "key", key}, args...)
+	}
+
+	uc.log.InfoContext(ctx, "Attempting to look up orders", withFields()...)
+
+	if orders := uc.cache.GetIndex(key); orders != nil {
+		uc.log.InfoContext(ctx, "Successfully got orders from cache", withFields("count", len(orders))...)
+		return orders, nil
+	}
+
+	orders, err := load(ctx)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "Failed to look up orders", withFields("error", err.Error())...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.cache.SetIndex(key, orders)
+
+	uc.log.InfoContext(ctx, "Successfully looked up orders", withFields("count", len(orders))...)
+
+	return orders, nil
+}
+
+// invalidateIndexes drops secondary cache keys an order could appear under,
+// so lookups pick up newly stored or changed orders.
+func (uc *UseCase) invalidateIndexes(order *model.Order) {
+	keys := make([]string, 0, len(order.Items)+2)
+	keys = append(keys,
+		trackIndexPrefix+order.TrackNumber,
+		transactionIndexPrefix+order.Payment.Transaction,
+	)
+	for _, item := range order.Items {
+		keys = append(keys, trackIndexPrefix+item.TrackNumber)
+	}
+
+	uc.cache.DeleteIndex(keys...)
+}
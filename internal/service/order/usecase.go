@@ -5,53 +5,140 @@ import (
 	"fmt"
 
 	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/shared/vo"
 	"github.com/D1sordxr/wb-tech-l0/internal/service/mapper"
 	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
 )
 
 type UseCase struct {
-	log   appPorts.Logger
-	repo  ports.OrderRepo
-	cache ports.OrderCache
+	log            appPorts.Logger
+	repo           ports.OrderRepo
+	cache          ports.OrderCache
+	invalidator    ports.CacheInvalidator
+	conflictPolicy ConflictPolicy
+	stats          ingestionCounters
+	loads          singleflight.Group
 }
 
 func NewUseCase(
 	log appPorts.Logger,
 	repo ports.OrderRepo,
 	cache ports.OrderCache,
+	invalidator ports.CacheInvalidator,
+	conflictPolicy ConflictPolicy,
 ) *UseCase {
 	return &UseCase{
-		log:   log,
-		repo:  repo,
-		cache: cache,
+		log:            log,
+		repo:           repo,
+		cache:          cache,
+		invalidator:    invalidator,
+		conflictPolicy: conflictPolicy,
 	}
 }
 
 func (uc *UseCase) CreateOrder(ctx context.Context, orderDTO dto.Order) error {
 	const op = "service.order.UseCase.CreateOrder"
+	return uc.createOrder(ctx, op, orderDTO, uc.resolveDuplicate)
+}
+
+func (uc *UseCase) SubmitOrder(ctx context.Context, orderDTO dto.Order) error {
+	const op = "service.order.UseCase.SubmitOrder"
+	return uc.createOrder(ctx, op, orderDTO, func(_ context.Context, orderModel *model.Order) error {
+		return fmt.Errorf("%s: order %s: %w", op, orderModel.OrderUID, orderErrs.ErrOrderAlreadyExists)
+	})
+}
+
+// createOrder stores a single order, handing an already stored one to onDuplicate.
+func (uc *UseCase) createOrder(
+	ctx context.Context,
+	op string,
+	orderDTO dto.Order,
+	onDuplicate func(ctx context.Context, orderModel *model.Order) error,
+) error {
 	withFields := func(args ...any) []any {
 		return append([]any{"op", op, "orderID", orderDTO.ID}, args...)
 	}
 
-	uc.log.Info("Attempting to create order", withFields()...)
+	ctx, span := startSpan(ctx, op, attribute.String("order.uid", orderDTO.ID))
+	defer span.End()
+
+	uc.log.InfoContext(ctx, "Attempting to create order", withFields()...)
 
 	orderModel := mapper.OrderFromDTO(orderDTO)
 
 	if err := uc.repo.CreateOrder(ctx, orderModel); err != nil {
-		uc.log.Info("Failed to create order", withFields("error", err.Error())...)
-		return fmt.Errorf("%s: %w", op, err)
+		if isDuplicate(err) {
+			return failSpan(span, onDuplicate(ctx, orderModel))
+		}
+		uc.log.InfoContext(ctx, "Failed to create order", withFields("error", err.Error())...)
+		return failSpan(span, fmt.Errorf("%s: %w", op, err))
 	}
 
+	uc.stats.created.Add(1)
 	uc.cache.Set(orderModel.OrderUID, orderModel)
+	uc.invalidateIndexes(orderModel)
+	// Other replicas may have cached the UID as missing before it was created.
+	uc.broadcastInvalidation(ctx, model.InvalidationCreated, orderModel.OrderUID)
 
-	uc.log.Info("Order created successfully", withFields()...)
+	uc.log.InfoContext(ctx, "Order created successfully", withFields()...)
 
 	return nil
 }
 
+func (uc *UseCase) CreateOrders(ctx context.Context, orderDTOs []dto.Order) ([]error, error) {
+	const op = "service.order.UseCase.CreateOrders"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "batchSize", len(orderDTOs)}, args...)
+	}
+
+	ctx, span := startSpan(ctx, op, attribute.Int("orders.count", len(orderDTOs)))
+	defer span.End()
+
+	uc.log.InfoContext(ctx, "Attempting to create orders", withFields()...)
+
+	orderModels := make([]*model.Order, len(orderDTOs))
+	for i, orderDTO := range orderDTOs {
+		orderModels[i] = mapper.OrderFromDTO(orderDTO)
+	}
+
+	errs, err := uc.repo.CreateOrders(ctx, orderModels)
+	if err != nil {
+		uc.log.InfoContext(ctx, "Failed to create orders", withFields("error", err.Error())...)
+		return nil, failSpan(span, fmt.Errorf("%s: %w", op, err))
+	}
+
+	failed := 0
+	created := make([]string, 0, len(orderModels))
+	for i, orderModel := range orderModels {
+		if isDuplicate(errs[i]) {
+			errs[i] = uc.resolveDuplicate(ctx, orderModel)
+		} else if errs[i] == nil {
+			uc.stats.created.Add(1)
+			uc.cache.Set(orderModel.OrderUID, orderModel)
+			uc.invalidateIndexes(orderModel)
+			created = append(created, orderModel.OrderUID)
+		}
+
+		if errs[i] != nil {
+			failed++
+			errs[i] = fmt.Errorf("%s: %w", op, errs[i])
+		}
+	}
+
+	uc.broadcastInvalidation(ctx, model.InvalidationCreated, created...)
+
+	span.SetAttributes(attribute.Int("orders.failed", failed))
+	uc.log.InfoContext(ctx, "Orders batch processed", withFields("created", len(orderModels)-failed, "failed", failed)...)
+
+	return errs, nil
+}
+
 func (uc *UseCase) GetByID(
 	ctx context.Context,
 	orderID string,
@@ -64,29 +151,129 @@ func (uc *UseCase) GetByID(
 		return append([]any{"op", op, "orderUID", orderID}, args...)
 	}
 
-	uc.log.Info("Attempting to get order", withFields()...)
+	ctx, span := startSpan(ctx, op, attribute.String("order.uid", orderID))
+	defer span.End()
+
+	uc.log.InfoContext(ctx, "Attempting to get order", withFields()...)
 
 	err := vo.ValidateUID(orderID)
 	if err != nil {
-		uc.log.Error("Failed to validate order", withFields("error", err.Error())...)
-		return nil, fmt.Errorf("%s: %w", op, err)
+		uc.log.ErrorContext(ctx, "Failed to validate order", withFields("error", err.Error())...)
+		return nil, failSpan(span, fmt.Errorf("%s: %w", op, err))
 	}
 
 	orderModel := uc.cache.Get(orderID)
+	span.SetAttributes(attribute.Bool("cache.hit", orderModel != nil))
 	if orderModel != nil {
-		uc.log.Info("Successfully got order from cache", withFields()...)
+		uc.log.InfoContext(ctx, "Successfully got order from cache", withFields()...)
+		return orderModel, nil
+	}
+
+	if uc.cache.IsMissing(orderID) {
+		uc.log.InfoContext(ctx, "Order is known to be missing", withFields()...)
+		return nil, fmt.Errorf("%s: %w", op, orderErrs.ErrOrderNotFount)
+	}
+
+	if orderModel = uc.cache.GetStale(orderID); orderModel != nil {
+		uc.log.InfoContext(ctx, "Serving stale order from cache while refreshing", withFields()...)
+		uc.refreshOrder(ctx, orderID)
 		return orderModel, nil
 	}
 
-	orderModel, err = uc.repo.GetOrder(ctx, orderID)
+	orderModel, err = uc.loadOrder(ctx, orderID)
 	if err != nil {
-		uc.log.Error("Failed to get order", withFields("error", err.Error())...)
+		uc.log.ErrorContext(ctx, "Failed to get order", withFields("error", err.Error())...)
+		return nil, failSpan(span, fmt.Errorf("%s: %w", op, err))
+	}
+
+	uc.log.InfoContext(ctx, "Successfully got order", withFields()...)
+
+	return orderModel, nil
+}
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+func (uc *UseCase) ListOrders(ctx context.Context, filter model.OrderFilter) (*model.OrderPage, error) {
+	const op = "service.order.UseCase.ListOrders"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op}, args...)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxPageLimit {
+		return nil, fmt.Errorf("%s: %w", op, orderErrs.ErrInvalidPageLimit)
+	}
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return nil, fmt.Errorf("%s: %w", op, orderErrs.ErrInvalidDateRange)
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+
+	orders, err := uc.repo.ListOrders(ctx, filter)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "Failed to list orders", withFields("error", err.Error())...)
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	uc.cache.Set(orderModel.OrderUID, orderModel)
+	page := &model.OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		last := page.Orders[limit-1]
+		page.NextCursor = &model.OrderCursor{
+			DateCreated: last.DateCreated,
+			OrderUID:    last.OrderUID,
+		}
+	}
 
-	uc.log.Info("Successfully got order", withFields()...)
+	uc.log.InfoContext(ctx, "Successfully listed orders", withFields("count", len(page.Orders))...)
 
-	return orderModel, nil
+	return page, nil
+}
+
+func (uc *UseCase) GetCustomerOrders(
+	ctx context.Context,
+	customerID string,
+	filter model.OrderFilter,
+) (
+	*model.CustomerOrders,
+	error,
+) {
+	const op = "service.order.UseCase.GetCustomerOrders"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "customerID", customerID}, args...)
+	}
+
+	uc.log.InfoContext(ctx, "Attempting to get customer orders", withFields()...)
+
+	summary, err := uc.repo.GetCustomerSummary(ctx, customerID)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "Failed to get customer summary", withFields("error", err.Error())...)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if summary.OrderCount == 0 {
+		return nil, fmt.Errorf("%s: %w", op, orderErrs.ErrCustomerNotFound)
+	}
+
+	filter.CustomerID = customerID
+	page, err := uc.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.log.InfoContext(ctx, "Successfully got customer orders", withFields("order_count", summary.OrderCount)...)
+
+	return &model.CustomerOrders{
+		Summary: summary,
+		Page:    page,
+	}, nil
+}
+
+func (uc *UseCase) IngestionStats() model.IngestionStats {
+	return uc.stats.snapshot()
 }
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultWarmUpLimit = 100
+	maxWarmUpLimit     = 100000
+)
+
+type Handler struct {
+	cache       ports.CacheAdmin
+	uc          ports.UseCase
+	invalidator ports.CacheInvalidator
+}
+
+func NewHandler(
+	cache ports.CacheAdmin,
+	uc ports.UseCase,
+	invalidator ports.CacheInvalidator,
+) *Handler {
+	return &Handler{
+		cache:       cache,
+		uc:          uc,
+		invalidator: invalidator,
+	}
+}
+
+func (h *Handler) stats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.cache.Stats())
+}
+
+func (h *Handler) keys(ctx *gin.Context) {
+	keys := h.cache.Keys()
+	ctx.JSON(http.StatusOK, gin.H{"count": len(keys), "order_uids": keys})
+}
+
+func (h *Handler) evict(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if !h.cache.Delete(id) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "order is not cached"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *Handler) flush(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"flushed": h.cache.Flush()})
+}
+
+func (h *Handler) warmUp(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), time.Minute)
+	defer cancel()
+
+	limit := defaultWarmUpLimit
+	if raw := ctx.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxWarmUpLimit {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"message": "limit must be between 1 and " + strconv.Itoa(maxWarmUpLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	loaded, err := h.cache.WarmUp(reqCtx, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"limit": limit, "loaded": loaded})
+}
+
+type invalidationRequest struct {
+	OrderUID string `json:"order_uid" binding:"required"`
+	Action   string `json:"action" binding:"required,oneof=evict refresh"`
+}
+
+// invalidate applies an invalidation on this replica and broadcasts it to the others,
+// for orders changed in storage outside the service.
+func (h *Handler) invalidate(ctx *gin.Context) {
+	var req invalidationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "order_uid is required and action must be evict or refresh",
+		})
+		return
+	}
+
+	invalidation := model.CacheInvalidation{
+		OrderUID: req.OrderUID,
+		Action:   model.InvalidationAction(req.Action),
+	}
+
+	if err := h.uc.ApplyInvalidation(ctx.Request.Context(), invalidation); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if err := h.invalidator.Invalidate(ctx.Request.Context(), invalidation); err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"message": "applied locally, but failed to notify other replicas"})
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+func (h *Handler) RegisterAdminRoutes(router gin.IRouter) {
+	router.GET("/cache/stats", h.stats)
+	router.GET("/cache/keys", h.keys)
+	router.DELETE("/cache/keys/:id", h.evict)
+	router.DELETE("/cache", h.flush)
+	router.POST("/cache/warm-up", h.warmUp)
+	router.POST("/cache/invalidations", h.invalidate)
+}
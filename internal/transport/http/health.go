@@ -0,0 +1,35 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// healthProbe serves the component breakdown of the health report with 200 when the
+// app is live (or ready, for the readiness probe) and 503 otherwise.
+func healthProbe(reporter ports.HealthReporter, readiness bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), healthCheckTimeout)
+		defer cancel()
+
+		report := reporter.HealthReport(checkCtx)
+
+		ok := report.Live
+		if readiness {
+			ok = report.Ready
+		}
+
+		status := http.StatusOK
+		if !ok {
+			status = http.StatusServiceUnavailable
+		}
+		ctx.JSON(status, report)
+	}
+}
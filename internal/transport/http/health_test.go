@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/app"
+	memoryCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// blockingInitializer holds the cache warm-up until release is closed.
+type blockingInitializer struct {
+	release chan struct{}
+}
+
+func (i blockingInitializer) GetOrdersForCache(ctx context.Context, _ int) ([]*model.Order, error) {
+	select {
+	case <-i.release:
+	case <-ctx.Done():
+	}
+	return nil, nil
+}
+
+func (i blockingInitializer) GetMostReadOrdersForCache(ctx context.Context, limit int) ([]*model.Order, error) {
+	return i.GetOrdersForCache(ctx, limit)
+}
+
+func (blockingInitializer) RecordOrderReads(context.Context, map[string]int64) error {
+	return nil
+}
+
+// idle runs until ctx is done.
+type idle struct{}
+
+func (idle) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (idle) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestReadyzWaitsForCacheWarmUp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	initializer := blockingInitializer{release: make(chan struct{})}
+	cache := memoryCache.NewCache(log, &config.Cache{
+		TTL:             time.Hour,
+		CleanupInterval: time.Minute,
+		WarmUp:          config.CacheWarmUp{Count: 10},
+		Shards:          1,
+	}, initializer)
+
+	health := app.NewHealth()
+	container := app.NewApp(log, &config.Supervisor{
+		Restart:    "never",
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Millisecond,
+		ResetAfter: time.Hour,
+		Critical:   []string{"postgres"},
+		Readiness:  []string{"cache"},
+	}, metrics.New(), health,
+		app.Named("postgres", idle{}),
+		app.Named("cache", cache),
+	)
+
+	router := gin.New()
+	router.GET("/readyz", healthProbe(health, true))
+	readyz := func() int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+	waitFor := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for readyz() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("/readyz = %d, want %d", readyz(), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		container.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Give the components time to start; the warm-up is still held.
+	time.Sleep(50 * time.Millisecond)
+	if code := readyz(); code != http.StatusServiceUnavailable {
+		t.Fatalf("/readyz during warm-up = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	close(initializer.release)
+	waitFor(http.StatusOK)
+}
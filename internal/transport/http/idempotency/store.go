@@ -0,0 +1,120 @@
+package idempotency
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+const HeaderKey = "Idempotency-Key"
+
+var (
+	ErrKeyInProgress = errors.New("request with this idempotency key is still in progress")
+	ErrKeyReused     = errors.New("idempotency key was already used with a different request")
+)
+
+type Response struct {
+	Status int
+	Body   []byte
+}
+
+type entry struct {
+	key         string
+	fingerprint string
+	response    *Response
+	expiresAt   time.Time
+}
+
+// Store remembers the response of every request made with an idempotency key, so a
+// client retrying the same request gets the original response instead of a second write.
+// Keys are kept in memory of the replica that served the request, at most maxEntries of
+// them: past that the key touched longest ago is forgotten first.
+type Store struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // oldest expiry at the front
+	ttl        time.Duration
+	maxEntries int
+}
+
+func NewStore(ttl time.Duration, maxEntries int) *Store {
+	return &Store{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		ttl:        ttl,
+		maxEntries: max(maxEntries, 1),
+	}
+}
+
+// Begin reserves key for a request identified by fingerprint. It returns the stored
+// response when the request was already completed and nil when the caller should proceed.
+func (s *Store) Begin(key, fingerprint string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if el, ok := s.entries[key]; ok {
+		e := el.Value.(*entry)
+		switch {
+		case e.fingerprint != fingerprint:
+			return nil, ErrKeyReused
+		case e.response == nil:
+			return nil, ErrKeyInProgress
+		default:
+			return e.response, nil
+		}
+	}
+
+	for s.order.Len() >= s.maxEntries {
+		s.remove(s.order.Front())
+	}
+
+	s.entries[key] = s.order.PushBack(&entry{
+		key:         key,
+		fingerprint: fingerprint,
+		expiresAt:   now.Add(s.ttl),
+	})
+
+	return nil, nil
+}
+
+func (s *Store) Complete(key string, status int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.response = &Response{Status: status, Body: body}
+		e.expiresAt = time.Now().Add(s.ttl)
+		s.order.MoveToBack(el)
+	}
+}
+
+// Abort releases key without storing a response, letting the client retry.
+func (s *Store) Abort(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+}
+
+// sweep drops expired keys. Every key lives for the same ttl, so they expire in list
+// order and the sweep stops at the first live one.
+func (s *Store) sweep(now time.Time) {
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		if now.Before(el.Value.(*entry).expiresAt) {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+func (s *Store) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*entry).key)
+}
@@ -0,0 +1,67 @@
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStoreReplaysCompletedRequest(t *testing.T) {
+	store := NewStore(time.Hour, 10)
+
+	if resp, err := store.Begin("key", "a"); resp != nil || err != nil {
+		t.Fatalf("first Begin = %v, %v, want nil, nil", resp, err)
+	}
+	if _, err := store.Begin("key", "a"); !errors.Is(err, ErrKeyInProgress) {
+		t.Fatalf("Begin during the request = %v, want %v", err, ErrKeyInProgress)
+	}
+
+	store.Complete("key", http.StatusCreated, []byte(`{}`))
+
+	resp, err := store.Begin("key", "a")
+	if err != nil || resp == nil || resp.Status != http.StatusCreated {
+		t.Fatalf("Begin after Complete = %v, %v, want the stored 201", resp, err)
+	}
+	if _, err := store.Begin("key", "b"); !errors.Is(err, ErrKeyReused) {
+		t.Fatalf("Begin with another body = %v, want %v", err, ErrKeyReused)
+	}
+}
+
+func TestStoreEvictsOldestPastBound(t *testing.T) {
+	store := NewStore(time.Hour, 3)
+
+	for i := range 5 {
+		key := fmt.Sprintf("key-%d", i)
+		if _, err := store.Begin(key, "a"); err != nil {
+			t.Fatalf("Begin(%s): %v", key, err)
+		}
+		store.Complete(key, http.StatusCreated, nil)
+	}
+
+	if n := store.order.Len(); n != 3 {
+		t.Fatalf("entries = %d, want 3", n)
+	}
+	for i, want := range []bool{false, false, true, true, true} {
+		if _, ok := store.entries[fmt.Sprintf("key-%d", i)]; ok != want {
+			t.Fatalf("key-%d kept = %t, want %t", i, ok, want)
+		}
+	}
+}
+
+func TestStoreSweepsExpiredKeys(t *testing.T) {
+	store := NewStore(20*time.Millisecond, 10)
+
+	for i := range 3 {
+		_, _ = store.Begin(fmt.Sprintf("key-%d", i), "a")
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if resp, err := store.Begin("key-0", "b"); resp != nil || err != nil {
+		t.Fatalf("Begin of an expired key = %v, %v, want nil, nil", resp, err)
+	}
+	if n := store.order.Len(); n != 1 {
+		t.Fatalf("entries = %d, want 1", n)
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	level ports.LogLevel
+}
+
+func NewHandler(level ports.LogLevel) *Handler {
+	return &Handler{level: level}
+}
+
+type levelRequest struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error"`
+}
+
+func (h *Handler) getLevel(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"level": h.level.Level()})
+}
+
+func (h *Handler) setLevel(ctx *gin.Context) {
+	var req levelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "level must be one of debug, info, warn, error"})
+		return
+	}
+
+	if err := h.level.SetLevel(req.Level); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"level": h.level.Level()})
+}
+
+func (h *Handler) RegisterAdminRoutes(router gin.IRouter) {
+	router.GET("/log/level", h.getLevel)
+	router.PUT("/log/level", h.setLevel)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog writes one structured record per request in place of gin's text logger.
+// Client errors are logged as warnings and server errors as errors.
+func AccessLog(log ports.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		status := ctx.Writer.Status()
+		fields := []any{
+			"method", ctx.Request.Method,
+			"route", route,
+			"path", ctx.Request.URL.Path,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+			// Size is -1 until something is written.
+			"bytes", max(ctx.Writer.Size(), 0),
+			"client_ip", ctx.ClientIP(),
+		}
+		if errs := ctx.Errors.ByType(gin.ErrorTypeAny); len(errs) > 0 {
+			fields = append(fields, "error", errs.String())
+		}
+
+		reqCtx := ctx.Request.Context()
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.ErrorContext(reqCtx, "HTTP request", fields...)
+		case status >= http.StatusBadRequest:
+			log.WarnContext(reqCtx, "HTTP request", fields...)
+		default:
+			log.InfoContext(reqCtx, "HTTP request", fields...)
+		}
+	}
+}
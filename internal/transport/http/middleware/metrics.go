@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so arbitrary paths
+// cannot blow up the number of label values.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the latency of every request by its route template and status.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.ObserveHTTPRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
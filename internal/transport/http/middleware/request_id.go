@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength bounds caller-provided IDs so they cannot bloat every log line.
+	maxRequestIDLength = 128
+)
+
+// RequestID takes the request ID from the X-Request-ID header or generates one, echoes it
+// in the response and makes it the correlation ID of everything logged for the request.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		ctx.Header(RequestIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(logger.WithCorrelationID(ctx.Request.Context(), id))
+
+		ctx.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII without spaces only, so a caller cannot
+// inject line breaks or control characters into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+type CreatedOrder struct {
+	OrderUID string `json:"order_uid"`
+}
+
+// FieldErrorsFromValidation lists every failed rule, naming fields by their JSON path.
+func FieldErrorsFromValidation(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fieldErrs := make([]FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		field := fieldErr.Namespace()
+		if _, path, ok := strings.Cut(field, "."); ok {
+			field = path
+		}
+
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   field,
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldErr.Error(),
+		})
+	}
+
+	return fieldErrs
+}
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ListOrdersQuery struct {
+	CustomerID      string    `form:"customer_id"`
+	TrackNumber     string    `form:"track_number"`
+	DeliveryService string    `form:"delivery_service"`
+	Locale          string    `form:"locale"`
+	Currency        string    `form:"currency"`
+	Provider        string    `form:"provider"`
+	Bank            string    `form:"bank"`
+	From            time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To              time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort            string    `form:"sort" binding:"omitempty,oneof=asc desc"`
+	Limit           int       `form:"limit"`
+	Cursor          string    `form:"cursor"`
+}
+
+func (q *ListOrdersQuery) ToFilter() (model.OrderFilter, error) {
+	filter := model.OrderFilter{
+		CustomerID:      q.CustomerID,
+		TrackNumber:     q.TrackNumber,
+		DeliveryService: q.DeliveryService,
+		Locale:          q.Locale,
+		Currency:        q.Currency,
+		Provider:        q.Provider,
+		Bank:            q.Bank,
+		CreatedFrom:     q.From.UTC(),
+		CreatedTo:       q.To.UTC(),
+		Ascending:       q.Sort == "asc",
+		Limit:           q.Limit,
+	}
+
+	if q.Cursor != "" {
+		cursor, err := DecodeCursor(q.Cursor)
+		if err != nil {
+			return model.OrderFilter{}, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+type OrderPage struct {
+	Orders     []*model.Order `json:"orders"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func OrderPageFromModel(page *model.OrderPage) OrderPage {
+	return OrderPage{
+		Orders:     page.Orders,
+		NextCursor: EncodeCursor(page.NextCursor),
+	}
+}
+
+func EncodeCursor(cursor *model.OrderCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	raw := cursor.DateCreated.UTC().Format(time.RFC3339Nano) + "|" + cursor.OrderUID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(value string) (*model.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	date, orderUID, found := strings.Cut(string(raw), "|")
+	if !found || orderUID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	dateCreated, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &model.OrderCursor{
+		DateCreated: dateCreated,
+		OrderUID:    orderUID,
+	}, nil
+}
+
+type CustomerOrders struct {
+	Summary    *model.CustomerSummary `json:"summary"`
+	Orders     []*model.Order         `json:"orders"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+func CustomerOrdersFromModel(history *model.CustomerOrders) CustomerOrders {
+	return CustomerOrders{
+		Summary:    history.Summary,
+		Orders:     history.Page.Orders,
+		NextCursor: EncodeCursor(history.Page.NextCursor),
+	}
+}
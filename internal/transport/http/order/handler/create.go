@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/idempotency"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/order/dto"
+	kafkaDTO "github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+	"github.com/D1sordxr/wb-tech-l0/pkg/errtool"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	maxOrderBodySize      = 1 << 20
+	idempotencyTTL        = 24 * time.Hour
+	idempotencyMaxEntries = 100_000
+)
+
+// newOrderValidator checks the same rules the Kafka reader does,
+// reporting fields under their JSON names.
+func newOrderValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validate
+}
+
+func (h *Handler) create(ctx *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxOrderBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	key := ctx.GetHeader(idempotency.HeaderKey)
+	if key == "" {
+		status, resp := h.createOrder(ctx.Request.Context(), body)
+		ctx.JSON(status, resp)
+		return
+	}
+
+	fingerprint := sha256.Sum256(body)
+	stored, err := h.idempotency.Begin(key, hex.EncodeToString(fingerprint[:]))
+	switch {
+	case errors.Is(err, idempotency.ErrKeyInProgress):
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	case errors.Is(err, idempotency.ErrKeyReused):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	case stored != nil:
+		ctx.Header("Idempotent-Replayed", "true")
+		ctx.Data(stored.Status, "application/json; charset=utf-8", stored.Body)
+		return
+	}
+
+	status, resp := h.createOrder(ctx.Request.Context(), body)
+	if status >= http.StatusInternalServerError {
+		h.idempotency.Abort(key)
+		ctx.JSON(status, resp)
+		return
+	}
+
+	respBody, err := json.Marshal(resp)
+	if err != nil {
+		h.idempotency.Abort(key)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	h.idempotency.Complete(key, status, respBody)
+
+	ctx.Data(status, "application/json; charset=utf-8", respBody)
+}
+
+func (h *Handler) createOrder(ctx context.Context, body []byte) (int, any) {
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var order kafkaDTO.Order
+	if err := json.Unmarshal(body, &order); err != nil {
+		return http.StatusBadRequest, gin.H{"message": err.Error()}
+	}
+
+	if err := h.validator.Struct(order); err != nil {
+		return http.StatusUnprocessableEntity, gin.H{
+			"message": "validation failed",
+			"errors":  dto.FieldErrorsFromValidation(err),
+		}
+	}
+
+	if err := h.getOrderUseCase.SubmitOrder(reqCtx, order); err != nil {
+		switch {
+		case errtool.In(
+			err,
+			orderErrs.ErrOrderAlreadyExists,
+			orderErrs.ErrOrderConflict,
+		):
+			return http.StatusConflict, gin.H{"message": err.Error()}
+		default:
+			return http.StatusInternalServerError, gin.H{"error": "internal error"}
+		}
+	}
+
+	return http.StatusCreated, dto.CreatedOrder{OrderUID: order.ID}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/idempotency"
+	kafkaDTO "github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// submitUseCase records SubmitOrder calls and fails them with err.
+type submitUseCase struct {
+	ports.UseCase
+	calls atomic.Int32
+	err   error
+}
+
+func (uc *submitUseCase) SubmitOrder(context.Context, kafkaDTO.Order) error {
+	uc.calls.Add(1)
+	return uc.err
+}
+
+func newTestRouter(uc ports.UseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHandler(uc).RegisterRoutes(router.Group("/api"))
+	return router
+}
+
+func validOrderBody(t *testing.T) []byte {
+	t.Helper()
+
+	order := mock.NewMockGenerator().GenerateOrder()
+	for i := range order.Items {
+		order.Items[i].Sale = 10 // the generator may pick 0, which fails validation
+	}
+	body, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("marshal order: %v", err)
+	}
+	return body
+}
+
+func postOrder(router http.Handler, body []byte, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", bytes.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   func(t *testing.T) []byte
+		err    error
+		status int
+	}{
+		{name: "created", body: validOrderBody, status: http.StatusCreated},
+		{name: "already exists", body: validOrderBody, err: orderErrs.ErrOrderAlreadyExists, status: http.StatusConflict},
+		{name: "conflict", body: validOrderBody, err: orderErrs.ErrOrderConflict, status: http.StatusConflict},
+		{
+			name:   "too large",
+			body:   func(*testing.T) []byte { return bytes.Repeat([]byte(" "), maxOrderBodySize+1) },
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "invalid order",
+			body:   func(*testing.T) []byte { return []byte(`{"order_uid":"b563feb7b2b84b6test"}`) },
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "malformed json",
+			body:   func(*testing.T) []byte { return []byte(`{`) },
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(&submitUseCase{err: tt.err})
+
+			if rec := postOrder(router, tt.body(t), ""); rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestCreateReplaysIdempotentRequest(t *testing.T) {
+	uc := &submitUseCase{}
+	router := newTestRouter(uc)
+	body := validOrderBody(t)
+
+	first := postOrder(router, body, "key")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d, want %d", first.Code, http.StatusCreated)
+	}
+
+	replay := postOrder(router, body, "key")
+	if replay.Code != http.StatusCreated || replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("replay = %d replayed=%q, want a replayed 201", replay.Code, replay.Header().Get("Idempotent-Replayed"))
+	}
+	if replay.Body.String() != first.Body.String() {
+		t.Fatalf("replay body = %s, want %s", replay.Body, first.Body)
+	}
+	if n := uc.calls.Load(); n != 1 {
+		t.Fatalf("SubmitOrder called %d times, want 1", n)
+	}
+
+	if rec := postOrder(router, validOrderBody(t), "key"); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestCreateRetriesAfterServerError(t *testing.T) {
+	uc := &submitUseCase{err: context.DeadlineExceeded}
+	router := newTestRouter(uc)
+	body := validOrderBody(t)
+
+	if rec := postOrder(router, body, "key"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("first status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	uc.err = nil
+	if rec := postOrder(router, body, "key"); rec.Code != http.StatusCreated {
+		t.Fatalf("retry status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if n := uc.calls.Load(); n != 2 {
+		t.Fatalf("SubmitOrder called %d times, want 2", n)
+	}
+}
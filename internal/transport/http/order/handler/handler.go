@@ -5,20 +5,29 @@ import (
 	"net/http"
 	"time"
 
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
 	sharedErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/shared/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/idempotency"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/order/dto"
 	"github.com/D1sordxr/wb-tech-l0/pkg/errtool"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 type Handler struct {
 	getOrderUseCase ports.UseCase
+	validator       *validator.Validate
+	idempotency     *idempotency.Store
 }
 
 func NewHandler(getOrderUseCase ports.UseCase) *Handler {
 	return &Handler{
 		getOrderUseCase: getOrderUseCase,
+		validator:       newOrderValidator(),
+		idempotency:     idempotency.NewStore(idempotencyTTL, idempotencyMaxEntries),
 	}
 }
 
@@ -41,6 +50,8 @@ func (h *Handler) getByID(ctx *gin.Context) {
 			sharedErrs.ErrOrderUIDInvalidLength,
 		):
 			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		case errtool.In(err, orderErrs.ErrOrderNotFount):
+			ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 		default:
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		}
@@ -50,9 +61,130 @@ func (h *Handler) getByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, &resp)
 }
 
+func (h *Handler) list(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var query dto.ListOrdersQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	filter, err := query.ToFilter()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := h.getOrderUseCase.ListOrders(reqCtx, filter)
+	if err != nil {
+		switch {
+		case errtool.In(
+			err,
+			orderErrs.ErrInvalidPageLimit,
+			orderErrs.ErrInvalidDateRange,
+		):
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.OrderPageFromModel(page))
+}
+
+func (h *Handler) getCustomerOrders(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	customerID := ctx.Param("id")
+	if customerID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "id required"})
+		return
+	}
+
+	var query dto.ListOrdersQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	filter, err := query.ToFilter()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	history, err := h.getOrderUseCase.GetCustomerOrders(reqCtx, customerID, filter)
+	if err != nil {
+		switch {
+		case errtool.In(err, orderErrs.ErrCustomerNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case errtool.In(
+			err,
+			orderErrs.ErrInvalidPageLimit,
+			orderErrs.ErrInvalidDateRange,
+		):
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.CustomerOrdersFromModel(history))
+}
+
+func (h *Handler) getByTrackNumber(ctx *gin.Context) {
+	h.lookup(ctx, ctx.Param("track"), h.getOrderUseCase.GetByTrackNumber)
+}
+
+func (h *Handler) getByTransactionID(ctx *gin.Context) {
+	h.lookup(ctx, ctx.Param("id"), h.getOrderUseCase.GetByTransactionID)
+}
+
+func (h *Handler) lookup(
+	ctx *gin.Context,
+	key string,
+	find func(ctx context.Context, key string) ([]*model.Order, error),
+) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if key == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "key required"})
+		return
+	}
+
+	resp, err := find(reqCtx, key)
+	if err != nil {
+		switch {
+		case errtool.In(err, orderErrs.ErrOrderNotFount):
+			ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) getIngestionStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.getOrderUseCase.IngestionStats())
+}
+
 func (h *Handler) RegisterRoutes(router gin.IRouter) {
 	router.GET("/order/:id", h.getByID)
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "ok"})
-	})
+	router.GET("/orders", h.list)
+	router.POST("/orders", h.create)
+	router.GET("/orders/by-track/:track", h.getByTrackNumber)
+	router.GET("/orders/by-transaction/:id", h.getByTransactionID)
+	router.GET("/customers/:id/orders", h.getCustomerOrders)
+}
+
+func (h *Handler) RegisterAdminRoutes(router gin.IRouter) {
+	router.GET("/ingestion/stats", h.getIngestionStats)
 }
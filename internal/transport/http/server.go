@@ -5,9 +5,12 @@ import (
 	"errors"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/http/middleware"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -15,21 +18,46 @@ type Handler interface {
 	RegisterRoutes(router gin.IRouter)
 }
 
+// AdminHandler exposes operational routes. They are served under /admin behind the
+// admin token and are not registered at all when no token is configured.
+type AdminHandler interface {
+	RegisterAdminRoutes(router gin.IRouter)
+}
+
 type Server struct {
-	log      ports.Logger
-	handlers []Handler
-	engine   *gin.Engine
-	server   *http.Server
+	log           ports.Logger
+	handlers      []Handler
+	adminHandlers []AdminHandler
+	adminToken    string
+	routesOnce    sync.Once
+	engine        *gin.Engine
+	server        *http.Server
 }
 
 func NewServer(
 	log ports.Logger,
 	config *config.HTTPServer,
+	metrics *metrics.Metrics,
+	health ports.HealthReporter,
+	adminHandlers []AdminHandler,
 	handlers ...Handler,
 ) *Server {
 	log.Info("Initializing HTTP server", "port", config.Port)
 
-	engine := gin.Default()
+	engine := gin.New()
+	engine.Use(middleware.Recovery(log))
+	// Registered before the remaining middleware, so scrapes and probes are neither traced, logged nor measured.
+	engine.GET("/metrics", gin.WrapH(metrics.Handler()))
+	engine.GET("/livez", healthProbe(health, false))
+	engine.GET("/readyz", healthProbe(health, true))
+	// Kept for existing probes, answers like /readyz.
+	engine.GET("/api/health", healthProbe(health, true))
+	engine.Use(
+		middleware.RequestID(),
+		middleware.Tracing(),
+		middleware.AccessLog(log),
+		middleware.Metrics(metrics),
+	)
 
 	if config.CORS {
 		allowedOrigins := config.AllowOrigins
@@ -40,8 +68,8 @@ func NewServer(
 		engine.Use(cors.New(cors.Config{
 			AllowOrigins:     allowedOrigins,
 			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-			ExposeHeaders:    []string{"Content-Length"},
+			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key", middleware.RequestIDHeader},
+			ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 			AllowCredentials: true,
 			MaxAge:           12 * time.Hour,
 		}))
@@ -56,18 +84,34 @@ func NewServer(
 			ReadTimeout:       config.Timeout,
 			WriteTimeout:      config.Timeout,
 		},
-		engine:   engine,
-		handlers: handlers,
+		engine:        engine,
+		handlers:      handlers,
+		adminHandlers: adminHandlers,
+		adminToken:    config.AdminToken,
 	}
 }
 
-func (s *Server) Run(_ context.Context) error {
+func (s *Server) registerRoutes() {
 	s.log.Info("Registering HTTP handlers...")
 	for _, handler := range s.handlers {
 		group := s.engine.Group("/api")
 		handler.RegisterRoutes(group)
 	}
 
+	if s.adminToken != "" {
+		adminGroup := s.engine.Group("/admin", middleware.AdminAuth(s.adminToken))
+		for _, handler := range s.adminHandlers {
+			handler.RegisterAdminRoutes(adminGroup)
+		}
+	} else if len(s.adminHandlers) > 0 {
+		s.log.Warn("Admin token is not configured, admin routes are disabled")
+	}
+}
+
+func (s *Server) Run(_ context.Context) error {
+	// Routes are registered once, Run is called again when the server is restarted.
+	s.routesOnce.Do(s.registerRoutes)
+
 	s.log.Info("Starting HTTP server...", "address", s.server.Addr)
 	if err := s.server.ListenAndServe(); err != nil {
 		if errors.Is(err, http.ErrServerClosed) {
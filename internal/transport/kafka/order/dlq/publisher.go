@@ -0,0 +1,119 @@
+package dlq
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+type Stage string
+
+const (
+	StageDecode   Stage = "decode"
+	StageValidate Stage = "validate"
+	StagePersist  Stage = "persist"
+	StageConflict Stage = "conflict"
+)
+
+const (
+	HeaderStage           = "x-dlq-stage"
+	HeaderError           = "x-dlq-error"
+	HeaderSourceTopic     = "x-dlq-source-topic"
+	HeaderSourcePartition = "x-dlq-source-partition"
+	HeaderSourceOffset    = "x-dlq-source-offset"
+	HeaderAttempts        = "x-dlq-attempts"
+)
+
+type Publisher struct {
+	log    appPorts.Logger
+	writer *kafka.Writer
+	topic  string
+}
+
+func NewPublisher(
+	log appPorts.Logger,
+	writer *kafka.Writer,
+) *Publisher {
+	return &Publisher{
+		log:    log,
+		writer: writer,
+		topic:  writer.GetDLQTopic(),
+	}
+}
+
+// Publish sends the original message to the dead-letter topic, keeping its key and value
+// and describing the failure in headers.
+func (p *Publisher) Publish(ctx context.Context, message kafkaLib.Message, stage Stage, cause error) error {
+	const op = "dlq.Publisher.Publish"
+
+	attempts := Attempts(message) + 1
+
+	headers := make([]kafkaLib.Header, 0, len(message.Headers)+6)
+	for _, header := range message.Headers {
+		if !isDLQHeader(header.Key) {
+			headers = append(headers, header)
+		}
+	}
+	headers = append(headers,
+		kafkaLib.Header{Key: HeaderStage, Value: []byte(stage)},
+		kafkaLib.Header{Key: HeaderError, Value: []byte(cause.Error())},
+		kafkaLib.Header{Key: HeaderSourceTopic, Value: []byte(message.Topic)},
+		kafkaLib.Header{Key: HeaderSourcePartition, Value: []byte(strconv.Itoa(message.Partition))},
+		kafkaLib.Header{Key: HeaderSourceOffset, Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		kafkaLib.Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+	)
+
+	dlqMessage := kafkaLib.Message{
+		Topic:   p.topic,
+		Key:     message.Key,
+		Value:   message.Value,
+		Headers: headers,
+	}
+	// Point the trace context at the processing attempt that gave up on the message.
+	tracing.Inject(ctx, &dlqMessage)
+
+	if err := p.writer.WriteMessages(ctx, dlqMessage); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	p.log.WarnContext(ctx, "Message sent to dead-letter topic",
+		"op", op,
+		"dlq_topic", p.topic,
+		"stage", stage,
+		"partition", message.Partition,
+		"offset", message.Offset,
+		"attempts", attempts,
+		"error", cause.Error(),
+	)
+
+	return nil
+}
+
+// Attempts returns how many times the message has already been dead-lettered.
+func Attempts(message kafkaLib.Message) int {
+	for _, header := range message.Headers {
+		if header.Key == HeaderAttempts {
+			attempts, err := strconv.Atoi(string(header.Value))
+			if err != nil {
+				return 0
+			}
+			return attempts
+		}
+	}
+	return 0
+}
+
+func isDLQHeader(key string) bool {
+	switch key {
+	case HeaderStage, HeaderError, HeaderSourceTopic, HeaderSourcePartition, HeaderSourceOffset, HeaderAttempts:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+type Redriver struct {
+	log         appPorts.Logger
+	reader      *kafka.Reader
+	writer      *kafka.Writer
+	targetTopic string
+}
+
+func NewRedriver(
+	log appPorts.Logger,
+	reader *kafka.Reader,
+	writer *kafka.Writer,
+) *Redriver {
+	return &Redriver{
+		log:         log,
+		reader:      reader,
+		writer:      writer,
+		targetTopic: writer.GetTopic(),
+	}
+}
+
+// Redrive moves dead-lettered messages back into the orders topic.
+// It stops after limit messages (0 means no limit) or when the topic stays idle for idleTimeout.
+func (r *Redriver) Redrive(ctx context.Context, limit int, idleTimeout time.Duration) (int, error) {
+	const op = "dlq.Redriver.Redrive"
+
+	redriven := 0
+	for limit <= 0 || redriven < limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+		message, err := r.reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				r.log.Info("Dead-letter topic drained", "op", op, "redriven", redriven)
+				return redriven, nil
+			}
+			return redriven, fmt.Errorf("%s: failed to fetch message: %w", op, err)
+		}
+
+		headers := make([]kafkaLib.Header, 0, len(message.Headers))
+		for _, header := range message.Headers {
+			if header.Key == HeaderAttempts || !isDLQHeader(header.Key) {
+				headers = append(headers, header)
+			}
+		}
+
+		if err = r.writer.WriteMessages(ctx, kafkaLib.Message{
+			Topic:   r.targetTopic,
+			Key:     message.Key,
+			Value:   message.Value,
+			Headers: headers,
+		}); err != nil {
+			return redriven, fmt.Errorf("%s: failed to write message: %w", op, err)
+		}
+
+		if err = r.reader.CommitMessages(ctx, message); err != nil {
+			return redriven, fmt.Errorf("%s: failed to commit message: %w", op, err)
+		}
+
+		r.log.Info("Message redriven",
+			"op", op,
+			"target_topic", r.targetTopic,
+			"partition", message.Partition,
+			"offset", message.Offset,
+			"stage", headerValue(message, HeaderStage),
+			"attempts", Attempts(message),
+		)
+		redriven++
+	}
+
+	return redriven, nil
+}
+
+func headerValue(message kafkaLib.Message, key string) string {
+	for _, header := range message.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,8 @@
+package dto
+
+type Invalidation struct {
+	OrderUID string `json:"order_uid"`
+	Action   string `json:"action"`
+	// Origin is the replica that published the invalidation.
+	Origin string `json:"origin"`
+}
@@ -0,0 +1,205 @@
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	memoryCache "github.com/D1sordxr/wb-tech-l0/internal/infrastructure/cache/memory/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/service/mapper"
+	orderService "github.com/D1sordxr/wb-tech-l0/internal/service/order"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+// storage serves orders from a map the test changes behind the replica's back.
+type storage struct {
+	ports.OrderRepo
+	mu     sync.Mutex
+	orders map[string]*model.Order
+}
+
+func (s *storage) GetOrder(_ context.Context, orderID string) (*model.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return nil, orderErrs.ErrOrderNotFount
+	}
+	return order, nil
+}
+
+func (s *storage) put(order *model.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[order.OrderUID] = order
+}
+
+type noopInitializer struct{}
+
+func (noopInitializer) GetOrdersForCache(context.Context, int) ([]*model.Order, error) {
+	return nil, nil
+}
+
+func (noopInitializer) GetMostReadOrdersForCache(context.Context, int) ([]*model.Order, error) {
+	return nil, nil
+}
+
+func (noopInitializer) RecordOrderReads(context.Context, map[string]int64) error {
+	return nil
+}
+
+// topic keeps the messages written by a publisher.
+type topic struct {
+	messages []kafkaLib.Message
+}
+
+func (w *topic) WriteMessages(_ context.Context, messages ...kafkaLib.Message) error {
+	w.messages = append(w.messages, messages...)
+	return nil
+}
+
+// replica is a listener applying invalidations to the local cache of its own use case.
+type replica struct {
+	listener *Listener
+	cache    *memoryCache.Cache
+	uc       *orderService.UseCase
+}
+
+func newReplica(t *testing.T, id string, repo ports.OrderRepo) *replica {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cache := memoryCache.NewCache(log, &config.Cache{
+		TTL:         time.Minute,
+		NegativeTTL: time.Minute,
+		Shards:      1,
+		Eviction:    config.CacheEviction{Policy: string(memoryCache.EvictionLRU), MaxEntries: 100},
+	}, noopInitializer{})
+	// Invalidations the replica publishes itself are not under test.
+	publisher := &Publisher{log: log, writer: &topic{}, replicaID: id}
+	uc := orderService.NewUseCase(log, repo, cache, publisher, orderService.ConflictReject)
+
+	return &replica{
+		listener: &Listener{log: log, uc: uc, replicaID: id},
+		cache:    cache,
+		uc:       uc,
+	}
+}
+
+// publish sends the invalidation from the writer replica and delivers it to r.
+func (r *replica) publish(t *testing.T, invalidation model.CacheInvalidation) {
+	t.Helper()
+
+	writer := &topic{}
+	publisher := &Publisher{log: r.listener.log, writer: writer, topic: "invalidations", replicaID: "writer"}
+	if err := publisher.Invalidate(context.Background(), invalidation); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	for _, message := range writer.messages {
+		r.listener.handle(context.Background(), message)
+	}
+}
+
+func newOrder() *model.Order {
+	return mapper.OrderFromDTO(mock.NewMockGenerator().GenerateOrder())
+}
+
+func TestPublisherKeysMessagesByOrder(t *testing.T) {
+	writer := &topic{}
+	publisher := &Publisher{
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		writer:    writer,
+		topic:     "invalidations",
+		replicaID: "writer",
+	}
+
+	err := publisher.Invalidate(context.Background(),
+		model.CacheInvalidation{OrderUID: "a", Action: model.InvalidationEvict},
+		model.CacheInvalidation{OrderUID: "b", Action: model.InvalidationRefresh},
+	)
+	if err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+
+	want := []dto.Invalidation{
+		{OrderUID: "a", Action: "evict", Origin: "writer"},
+		{OrderUID: "b", Action: "refresh", Origin: "writer"},
+	}
+	if len(writer.messages) != len(want) {
+		t.Fatalf("wrote %d messages, want %d", len(writer.messages), len(want))
+	}
+	for i, message := range writer.messages {
+		var got dto.Invalidation
+		if err = json.Unmarshal(message.Value, &got); err != nil {
+			t.Fatalf("decode message %d: %v", i, err)
+		}
+		if got != want[i] || string(message.Key) != want[i].OrderUID || message.Topic != "invalidations" {
+			t.Fatalf("message %d = %s %+v, want key %s and %+v", i, message.Key, got, want[i].OrderUID, want[i])
+		}
+	}
+}
+
+func TestListenerEvicts(t *testing.T) {
+	order := newOrder()
+	repo := &storage{orders: map[string]*model.Order{order.OrderUID: order}}
+	r := newReplica(t, "reader", repo)
+
+	if _, err := r.uc.GetByID(context.Background(), order.OrderUID); err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	r.publish(t, model.CacheInvalidation{OrderUID: order.OrderUID, Action: model.InvalidationEvict})
+
+	if r.cache.Peek(order.OrderUID) != nil {
+		t.Fatal("evicted order is still cached")
+	}
+}
+
+func TestListenerRefreshes(t *testing.T) {
+	order := newOrder()
+	repo := &storage{orders: map[string]*model.Order{order.OrderUID: order}}
+	r := newReplica(t, "reader", repo)
+
+	if _, err := r.uc.GetByID(context.Background(), order.OrderUID); err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	changed := *order
+	changed.TrackNumber = "CHANGED"
+	repo.put(&changed)
+	r.publish(t, model.CacheInvalidation{OrderUID: order.OrderUID, Action: model.InvalidationRefresh})
+
+	cached := r.cache.Peek(order.OrderUID)
+	if cached == nil || cached.TrackNumber != "CHANGED" {
+		t.Fatalf("cached order = %+v, want the refreshed copy", cached)
+	}
+}
+
+func TestListenerSkipsOwnInvalidations(t *testing.T) {
+	order := newOrder()
+	repo := &storage{orders: map[string]*model.Order{order.OrderUID: order}}
+	r := newReplica(t, "writer", repo)
+
+	if _, err := r.uc.GetByID(context.Background(), order.OrderUID); err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	r.publish(t, model.CacheInvalidation{OrderUID: order.OrderUID, Action: model.InvalidationEvict})
+
+	if r.cache.Peek(order.OrderUID) == nil {
+		t.Fatal("the publishing replica applied its own invalidation")
+	}
+}
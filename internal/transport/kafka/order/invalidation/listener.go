@@ -0,0 +1,162 @@
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/logger"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+// Listener applies invalidations published by any replica to the local cache.
+// It reads through a broadcast consumer, so every replica sees every invalidation.
+type Listener struct {
+	log            appPorts.Logger
+	consumer       *kafka.GroupConsumer
+	uc             ports.UseCase
+	replicaID      string
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	mu             sync.Mutex
+	done           chan struct{}
+}
+
+func NewListener(
+	log appPorts.Logger,
+	consumer *kafka.GroupConsumer,
+	uc ports.UseCase,
+	cfg *config.Kafka,
+) *Listener {
+	return &Listener{
+		log:            log,
+		consumer:       consumer,
+		uc:             uc,
+		replicaID:      cfg.ReplicaID,
+		initialBackoff: cfg.Retry.InitialBackoff,
+		maxBackoff:     max(cfg.Retry.MaxBackoff, cfg.Retry.InitialBackoff),
+		done:           make(chan struct{}),
+	}
+}
+
+func (l *Listener) consumePartition(ctx context.Context, partition *kafka.PartitionReader) {
+	const op = "invalidation.Listener.consumePartition"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "partition", partition.ID()}, args...)
+	}
+
+	// Fetch errors are retried with backoff: giving up would stop the partition's
+	// invalidations until the next rebalance while the replica serves stale orders.
+	backoff := l.initialBackoff
+	for {
+		message, err := partition.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, kafka.ErrGenerationEnded) {
+				return
+			}
+			l.log.Error("Failed to fetch invalidation, retrying",
+				withFields("backoff", backoff, "error", err.Error())...,
+			)
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, l.maxBackoff)
+			continue
+		}
+		backoff = l.initialBackoff
+
+		l.handle(ctx, message)
+
+		if err = partition.CommitMessages(message); err != nil {
+			l.log.Error("Failed to commit invalidation", withFields("offset", message.Offset, "error", err.Error())...)
+		}
+	}
+}
+
+// handle applies a single invalidation. Failures are logged and not retried:
+// the use case has already dropped the affected entry by then.
+func (l *Listener) handle(ctx context.Context, message kafkaLib.Message) {
+	const op = "invalidation.Listener.handle"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "partition", message.Partition, "offset", message.Offset}, args...)
+	}
+
+	ctx, span := tracing.StartProcess(logger.WithCorrelationID(ctx, kafka.MessageID(message)), message)
+	defer span.End()
+
+	var invalidation dto.Invalidation
+	if err := json.Unmarshal(message.Value, &invalidation); err != nil {
+		l.log.ErrorContext(ctx, "Failed to decode invalidation", withFields("error", err.Error())...)
+		return
+	}
+
+	// The publishing replica updated its own cache before publishing.
+	if invalidation.Origin == l.replicaID {
+		return
+	}
+
+	ctx = logger.WithFields(ctx, "order_uid", invalidation.OrderUID)
+	if err := l.uc.ApplyInvalidation(ctx, model.CacheInvalidation{
+		OrderUID: invalidation.OrderUID,
+		Action:   model.InvalidationAction(invalidation.Action),
+	}); err != nil {
+		l.log.ErrorContext(ctx, "Failed to apply invalidation",
+			withFields("origin", invalidation.Origin, "error", err.Error())...,
+		)
+	}
+}
+
+func (l *Listener) Start(ctx context.Context) error {
+	// Every run gets its own done channel, so the listener can be started again after a failure.
+	done := make(chan struct{})
+	l.mu.Lock()
+	l.done = done
+	l.mu.Unlock()
+	defer close(done)
+
+	l.log.Info("Starting cache invalidation listener",
+		"topic", l.consumer.GetTopic(),
+		"replica", l.replicaID,
+	)
+
+	return l.consumer.Consume(ctx, l.consumePartition)
+}
+
+func (l *Listener) runDone() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.done
+}
+
+func (l *Listener) Stop(ctx context.Context) error {
+	l.log.Info("Stopping cache invalidation listener")
+
+	select {
+	case <-l.runDone():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,86 @@
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/model"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// messageWriter is the part of kafka.Writer the publisher writes with.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafkaLib.Message) error
+}
+
+type Publisher struct {
+	log       appPorts.Logger
+	writer    messageWriter
+	topic     string
+	replicaID string
+}
+
+func NewPublisher(
+	log appPorts.Logger,
+	writer *kafka.Writer,
+	cfg *config.Kafka,
+) *Publisher {
+	return &Publisher{
+		log:       log,
+		writer:    writer,
+		topic:     writer.GetInvalidationTopic(),
+		replicaID: cfg.ReplicaID,
+	}
+}
+
+// Invalidate publishes the invalidations in one write, each keyed by its order UID, so
+// invalidations of one order reach every replica in the order they were published.
+func (p *Publisher) Invalidate(ctx context.Context, invalidations ...model.CacheInvalidation) error {
+	const op = "invalidation.Publisher.Invalidate"
+
+	if len(invalidations) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.StartPublish(ctx, p.topic, len(invalidations))
+	defer span.End()
+
+	messages := make([]kafkaLib.Message, len(invalidations))
+	for i, invalidation := range invalidations {
+		value, err := json.Marshal(dto.Invalidation{
+			OrderUID: invalidation.OrderUID,
+			Action:   string(invalidation.Action),
+			Origin:   p.replicaID,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		messages[i] = kafkaLib.Message{
+			Topic: p.topic,
+			Key:   []byte(invalidation.OrderUID),
+			Value: value,
+		}
+		tracing.Inject(ctx, &messages[i])
+	}
+
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	p.log.InfoContext(ctx, "Cache invalidations published",
+		"op", op,
+		"count", len(invalidations),
+	)
+
+	return nil
+}
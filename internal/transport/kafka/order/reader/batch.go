@@ -0,0 +1,170 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/logger"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+type batchPolicy struct {
+	enabled bool
+	size    int
+	timeout time.Duration
+}
+
+func newBatchPolicy(cfg *config.ConsumerBatch) batchPolicy {
+	policy := batchPolicy{
+		enabled: cfg.Enabled,
+		size:    cfg.Size,
+		timeout: cfg.Timeout,
+	}
+
+	if policy.size < 1 {
+		policy.size = 1
+	}
+	if policy.timeout <= 0 {
+		policy.timeout = 200 * time.Millisecond
+	}
+
+	return policy
+}
+
+// processBatches collects up to batch.size messages, or whatever arrived within
+// batch.timeout, and persists them together. Offsets are committed only after the
+// whole batch has been stored or dead-lettered.
+func (r *Reader) processBatches(ctx context.Context, partition partitionCommitter, messages <-chan kafkaLib.Message) {
+	const op = "kafka.Reader.processBatches"
+
+	batch := make([]kafkaLib.Message, 0, r.batch.size)
+	timer := time.NewTimer(r.batch.timeout)
+	timer.Stop()
+
+	stalled := false
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if !stalled && !r.processBatch(ctx, partition, batch) {
+			stalled = true
+			r.log.Warn("partition stalled, leaving remaining messages uncommitted",
+				"op", op,
+				"partition", partition.ID(),
+				"offset", batch[0].Offset,
+			)
+		}
+		for range batch {
+			<-r.inFlight
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				timer.Stop()
+				flush()
+				return
+			}
+			batch = append(batch, message)
+			if len(batch) == 1 {
+				timer.Reset(r.batch.timeout)
+			}
+			if len(batch) >= r.batch.size {
+				timer.Stop()
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+func (r *Reader) processBatch(ctx context.Context, partition partitionCommitter, batch []kafkaLib.Message) bool {
+	const op = "kafka.Reader.processBatch"
+	withFields := func(args ...any) []any {
+		return append([]any{
+			"op", op,
+			"partition", partition.ID(),
+			"first_offset", batch[0].Offset,
+			"batch_size", len(batch),
+		}, args...)
+	}
+
+	ctx, span := tracing.StartProcessBatch(logger.WithCorrelationID(ctx, kafka.BatchID(batch)), r.topic, batch)
+	defer span.End()
+
+	orders := make([]dto.Order, 0, len(batch))
+	sources := make([]kafkaLib.Message, 0, len(batch))
+	for _, message := range batch {
+		msg, stage, err := r.decodeMessage(message)
+		if err != nil {
+			if !r.reject(messageContext(ctx, message), message, stage, err) {
+				return false
+			}
+			continue
+		}
+		orders = append(orders, msg)
+		sources = append(sources, message)
+	}
+
+	if len(orders) > 0 {
+		var errs []error
+		err := r.persistWithRetry(ctx, withFields, func(ctx context.Context) error {
+			var err error
+			errs, err = r.uc.CreateOrders(ctx, orders)
+			return err
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				r.log.WarnContext(ctx, "stopped while persisting batch, leaving messages uncommitted", withFields()...)
+				return false
+			}
+			r.log.ErrorContext(ctx, "failed to create orders batch, falling back to single inserts",
+				withFields("error", err.Error())...,
+			)
+			errs = make([]error, len(orders))
+			for i := range orders {
+				errs[i] = r.createOrder(orderContext(messageContext(ctx, sources[i]), orders[i]), sources[i], orders[i])
+			}
+		}
+
+		for i, orderErr := range errs {
+			orderCtx := orderContext(messageContext(ctx, sources[i]), orders[i])
+			if orderErr != nil && isRetryable(orderErr) {
+				orderErr = r.createOrder(orderCtx, sources[i], orders[i])
+			}
+			if orderErr == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				r.log.WarnContext(ctx, "stopped while persisting batch, leaving messages uncommitted", withFields()...)
+				return false
+			}
+			r.log.ErrorContext(orderCtx, "failed to create order", withFields("error", orderErr.Error())...)
+			if !r.deadLetter(orderCtx, sources[i], persistStage(orderErr), orderErr) {
+				return false
+			}
+		}
+	}
+
+	if err := partition.CommitMessages(batch...); err != nil {
+		if errors.Is(err, kafka.ErrGenerationEnded) {
+			r.log.WarnContext(ctx, "partition revoked, batch left for redelivery", withFields()...)
+			return true
+		}
+		r.log.ErrorContext(ctx, "failed to commit batch", withFields("error", err.Error())...)
+		return true
+	}
+	r.metrics.MessagesCommitted(r.topic, len(batch))
+
+	return true
+}
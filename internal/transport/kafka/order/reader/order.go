@@ -3,81 +3,313 @@ package reader
 import (
 	"context"
 	"encoding/json"
-	"time"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 
 	appPorts "github.com/D1sordxr/wb-tech-l0/internal/domain/app/ports"
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
 	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
 	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/kafka"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/logger"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/tracing"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dlq"
 	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
 
 	"github.com/go-playground/validator/v10"
+	kafkaLib "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
 )
 
+var errUnexpectedTopic = errors.New("unexpected message topic")
+
+// stageUnexpectedTopic labels skipped messages of a foreign topic in the failure metrics.
+const stageUnexpectedTopic = "unexpected_topic"
+
+// deadLetterPublisher sends messages the reader gave up on to the dead-letter topic.
+type deadLetterPublisher interface {
+	Publish(ctx context.Context, message kafkaLib.Message, stage dlq.Stage, cause error) error
+}
+
+// partitionCommitter is the part of kafka.PartitionReader that processing needs.
+type partitionCommitter interface {
+	ID() int
+	CommitMessages(messages ...kafkaLib.Message) error
+}
+
 type Reader struct {
-	log       appPorts.Logger
-	reader    *kafka.Reader
-	validator *validator.Validate
-	uc        ports.UseCase
-	topic     string
+	log         appPorts.Logger
+	consumer    *kafka.GroupConsumer
+	validator   *validator.Validate
+	uc          ports.UseCase
+	dlq         deadLetterPublisher
+	metrics     *metrics.Metrics
+	retry       retryPolicy
+	batch       batchPolicy
+	topic       string
+	inFlight    chan struct{}
+	paused      atomic.Int32
+	mu          sync.Mutex
+	done        chan struct{}
+	drainCtx    context.Context
+	cancelDrain context.CancelFunc
 }
 
 func NewReader(
 	log appPorts.Logger,
-	reader *kafka.Reader,
+	consumer *kafka.GroupConsumer,
 	uc ports.UseCase,
-	topic string,
+	dlqPublisher *dlq.Publisher,
+	metrics *metrics.Metrics,
+	cfg *config.Kafka,
 ) *Reader {
+	maxInFlight := cfg.MaxInFlight
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+
+	drainCtx, cancelDrain := context.WithCancel(context.Background())
+
 	return &Reader{
-		log:       log,
-		reader:    reader,
-		uc:        uc,
-		topic:     topic,
-		validator: validator.New(),
+		log:         log,
+		consumer:    consumer,
+		uc:          uc,
+		dlq:         dlqPublisher,
+		metrics:     metrics,
+		retry:       newRetryPolicy(&cfg.Retry),
+		batch:       newBatchPolicy(&cfg.Batch),
+		topic:       consumer.GetTopic(),
+		validator:   validator.New(),
+		inFlight:    make(chan struct{}, maxInFlight),
+		done:        make(chan struct{}),
+		drainCtx:    drainCtx,
+		cancelDrain: cancelDrain,
 	}
 }
 
-func (r *Reader) processMessage(ctx context.Context) {
+// consumePartition fetches messages of one partition as fast as the broker delivers them
+// and processes them strictly in order on a separate goroutine, committing after each one.
+// Fetching stops when ctx is done; already fetched messages are drained with drainCtx.
+func (r *Reader) consumePartition(ctx, drainCtx context.Context, partition *kafka.PartitionReader) {
+	const op = "kafka.Reader.consumePartition"
+	withFields := func(args ...any) []any {
+		return append([]any{"op", op, "partition", partition.ID()}, args...)
+	}
+
+	r.log.Info("Partition assigned", withFields()...)
+
+	messages := make(chan kafkaLib.Message, cap(r.inFlight))
+	processed := make(chan struct{})
+
+	go func() {
+		defer close(processed)
+
+		if r.batch.enabled {
+			r.processBatches(drainCtx, partition, messages)
+			return
+		}
+
+		stalled := false
+		for message := range messages {
+			if !stalled && !r.processMessage(drainCtx, partition, message) {
+				stalled = true
+				r.log.Warn("partition stalled, leaving remaining messages uncommitted",
+					withFields("offset", message.Offset)...,
+				)
+			}
+			<-r.inFlight
+		}
+	}()
+
+	for {
+		select {
+		case r.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			close(messages)
+			<-processed
+			r.log.Info("Partition drained", withFields()...)
+			return
+		}
+
+		message, err := partition.FetchMessage(ctx)
+		if err != nil {
+			<-r.inFlight
+			if ctx.Err() == nil {
+				r.log.Error("failed to fetch message", withFields("error", err.Error())...)
+				sleep(ctx, r.retry.initialBackoff)
+			}
+			continue
+		}
+
+		r.metrics.MessageConsumed(message.Topic, message.Partition, message.HighWaterMark, message.Offset)
+		messages <- message
+	}
+}
+
+func (r *Reader) processMessage(ctx context.Context, partition partitionCommitter, message kafkaLib.Message) bool {
 	const op = "kafka.Reader.processMessage"
+
+	if !r.handleMessage(ctx, message) {
+		return false
+	}
+
+	if err := partition.CommitMessages(message); err != nil {
+		if errors.Is(err, kafka.ErrGenerationEnded) {
+			r.log.WarnContext(messageContext(ctx, message), "partition revoked, message left for redelivery",
+				"op", op,
+				"partition", message.Partition,
+				"offset", message.Offset,
+			)
+			return true
+		}
+		r.log.ErrorContext(messageContext(ctx, message), "failed to commit message",
+			"op", op,
+			"partition", message.Partition,
+			"offset", message.Offset,
+			"error", err.Error(),
+		)
+		return true
+	}
+	r.metrics.MessagesCommitted(r.topic, 1)
+
+	return true
+}
+
+// handleMessage reports whether the message was dealt with and its offset can be committed.
+func (r *Reader) handleMessage(ctx context.Context, message kafkaLib.Message) bool {
+	const op = "kafka.Reader.handleMessage"
 	withFields := func(args ...any) []any {
-		return append([]any{"op", op}, args...)
+		return append([]any{
+			"op", op,
+			"partition", message.Partition,
+			"offset", message.Offset,
+		}, args...)
 	}
 
-	message, err := r.reader.FetchMessage(ctx)
+	ctx, span := tracing.StartProcess(messageContext(ctx, message), message)
+	defer span.End()
+
+	msg, stage, err := r.decodeMessage(message)
 	if err != nil {
-		r.log.Error("failed to fetch message", withFields("error", err.Error())...)
-		return
+		span.SetStatus(codes.Error, err.Error())
+		return r.reject(ctx, message, stage, err)
 	}
 
-	var commitErr error
-	defer func() {
-		if commitErr == nil {
-			if kafkaCommitErr := r.reader.CommitMessages(ctx, message); kafkaCommitErr != nil {
-				r.log.Error("failed to commit message", withFields("error", kafkaCommitErr.Error())...)
-			}
-		} else {
-			r.log.Error("failed to commit message due storage error", withFields("error", commitErr.Error())...)
+	ctx = orderContext(ctx, msg)
+	if err = r.createOrder(ctx, message, msg); err != nil {
+		if ctx.Err() != nil {
+			r.log.WarnContext(ctx, "stopped while persisting order, leaving message uncommitted", withFields()...)
+			return false
 		}
-	}()
+		r.log.ErrorContext(ctx, "failed to create order", withFields("error", err.Error())...)
+		span.SetStatus(codes.Error, err.Error())
+		return r.deadLetter(ctx, message, persistStage(err), err)
+	}
+
+	return true
+}
+
+// messageContext makes topic/partition/offset of the message the correlation ID
+// of everything logged while it is processed.
+func messageContext(ctx context.Context, message kafkaLib.Message) context.Context {
+	return logger.WithCorrelationID(ctx, kafka.MessageID(message))
+}
+
+// orderContext adds the order UID of a decoded message to the logged fields.
+func orderContext(ctx context.Context, msg dto.Order) context.Context {
+	return logger.WithFields(ctx, "order_uid", msg.ID)
+}
+
+func persistStage(err error) dlq.Stage {
+	if errors.Is(err, orderErrs.ErrOrderConflict) {
+		return dlq.StageConflict
+	}
+	return dlq.StagePersist
+}
+
+func (r *Reader) decodeMessage(message kafkaLib.Message) (dto.Order, dlq.Stage, error) {
+	var msg dto.Order
 
 	if message.Topic != r.topic {
-		r.log.Error("expected message to have exact topic",
+		return msg, "", errUnexpectedTopic
+	}
+
+	if err := json.Unmarshal(message.Value, &msg); err != nil {
+		return msg, dlq.StageDecode, err
+	}
+
+	if err := r.validator.Struct(msg); err != nil {
+		return msg, dlq.StageValidate, err
+	}
+
+	return msg, "", nil
+}
+
+// reject handles a message that failed decoding: messages from a foreign topic are
+// skipped, everything else goes to the dead-letter topic.
+func (r *Reader) reject(ctx context.Context, message kafkaLib.Message, stage dlq.Stage, cause error) bool {
+	const op = "kafka.Reader.reject"
+	withFields := func(args ...any) []any {
+		return append([]any{
+			"op", op,
+			"partition", message.Partition,
+			"offset", message.Offset,
+		}, args...)
+	}
+
+	if errors.Is(cause, errUnexpectedTopic) {
+		r.metrics.MessageFailed(message.Topic, stageUnexpectedTopic)
+		r.log.ErrorContext(ctx, "expected message to have exact topic",
 			withFields("message_topic", message.Topic)...,
 		)
-		return
+		return true
 	}
 
-	var msg dto.Order
-	if err = json.Unmarshal(message.Value, &msg); err != nil {
-		r.log.Error("failed to unmarshal message", withFields("error", err.Error())...)
-		return
-	}
+	r.log.ErrorContext(ctx, "failed to "+string(stage)+" message", withFields("error", cause.Error())...)
+	return r.deadLetter(ctx, message, stage, cause)
+}
 
-	if err = r.validator.Struct(msg); err != nil {
-		r.log.Error("failed to validate message", withFields("error", err.Error())...)
+func (r *Reader) createOrder(ctx context.Context, message kafkaLib.Message, msg dto.Order) error {
+	const op = "kafka.Reader.createOrder"
+	withFields := func(args ...any) []any {
+		return append([]any{
+			"op", op,
+			"partition", message.Partition,
+			"offset", message.Offset,
+		}, args...)
 	}
-	if commitErr = r.uc.CreateOrder(ctx, msg); err != nil {
-		r.log.Error("failed to create order", withFields("error", err.Error())...)
-		return
+
+	return r.persistWithRetry(ctx, withFields, func(ctx context.Context) error {
+		return r.uc.CreateOrder(ctx, msg)
+	})
+}
+
+func (r *Reader) deadLetter(ctx context.Context, message kafkaLib.Message, stage dlq.Stage, cause error) bool {
+	r.metrics.MessageFailed(message.Topic, string(stage))
+
+	for attempt := 1; ; attempt++ {
+		err := r.dlq.Publish(ctx, message, stage, cause)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		backoff := r.retry.backoff(attempt)
+		r.log.ErrorContext(ctx, "failed to publish message to dead-letter topic, retrying",
+			"stage", stage,
+			"partition", message.Partition,
+			"offset", message.Offset,
+			"backoff", backoff,
+			"error", err.Error(),
+		)
+		if !sleep(ctx, backoff) {
+			return false
+		}
 	}
 }
 
@@ -86,23 +318,49 @@ func (r *Reader) Start(ctx context.Context) error {
 	withFields := func(args ...any) []any {
 		return append([]any{"op", op}, args...)
 	}
+	// Every run gets its own done channel, so the reader can be started again after a failure.
+	done := make(chan struct{})
+	r.mu.Lock()
+	r.done = done
+	r.mu.Unlock()
+	defer close(done)
 
-	r.log.Info("Starting kafka reader", withFields()...)
+	r.log.Info("Starting kafka reader",
+		withFields("max_in_flight", cap(r.inFlight), "batch_enabled", r.batch.enabled, "batch_size", r.batch.size)...,
+	)
 
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
+	return r.consumer.Consume(ctx, func(partitionCtx context.Context, partition *kafka.PartitionReader) {
+		r.consumePartition(partitionCtx, r.drainCtx, partition)
+	})
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			r.processMessage(ctx)
-		}
+func (r *Reader) runDone() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.done
+}
+
+// HealthCheck reports the reader not ready while partitions are paused
+// because storage kept failing.
+func (r *Reader) HealthCheck(_ context.Context) appPorts.Health {
+	if paused := r.paused.Load(); paused > 0 {
+		return appPorts.NotReady(fmt.Sprintf("%d partition(s) paused after storage errors", paused))
 	}
+	return appPorts.Up()
 }
 
-func (r *Reader) Stop(_ context.Context) error {
+// Stop waits for in-flight messages to be processed and committed. If ctx expires
+// first, processing is cancelled and the remaining messages stay uncommitted.
+func (r *Reader) Stop(ctx context.Context) error {
 	r.log.Info("Stopping kafka reader")
-	return nil
+
+	select {
+	case <-r.runDone():
+		r.log.Info("Kafka reader drained")
+		return nil
+	case <-ctx.Done():
+		r.cancelDrain()
+		r.log.Warn("Kafka reader drain interrupted", "error", ctx.Err().Error())
+		return ctx.Err()
+	}
 }
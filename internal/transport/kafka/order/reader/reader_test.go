@@ -0,0 +1,266 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/ports"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/metrics"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/mock"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dlq"
+	"github.com/D1sordxr/wb-tech-l0/internal/transport/kafka/order/dto"
+
+	"github.com/go-playground/validator/v10"
+	kafkaLib "github.com/segmentio/kafka-go"
+)
+
+const testTopic = "orders"
+
+var errTransient = fmt.Errorf("connection reset: %w", orderErrs.ErrStorageUnavailable)
+
+// storage fails CreateOrder with the queued errors of each order, then succeeds.
+type storage struct {
+	ports.UseCase
+	mu        sync.Mutex
+	errs      map[string][]error
+	batchErrs []error
+	created   []string
+}
+
+func (s *storage) CreateOrder(_ context.Context, order dto.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if errs := s.errs[order.ID]; len(errs) > 0 {
+		s.errs[order.ID] = errs[1:]
+		if errs[0] != nil {
+			return errs[0]
+		}
+	}
+	s.created = append(s.created, order.ID)
+	return nil
+}
+
+func (s *storage) CreateOrders(_ context.Context, orders []dto.Order) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	errs := make([]error, len(orders))
+	for i, order := range orders {
+		if i < len(s.batchErrs) && s.batchErrs[i] != nil {
+			errs[i] = s.batchErrs[i]
+			continue
+		}
+		s.created = append(s.created, order.ID)
+	}
+	return errs, nil
+}
+
+type deadLetter struct {
+	offset int64
+	stage  dlq.Stage
+}
+
+// deadLetters records published messages, failing the first failures calls.
+type deadLetters struct {
+	mu        sync.Mutex
+	failures  int
+	published []deadLetter
+}
+
+func (d *deadLetters) Publish(_ context.Context, message kafkaLib.Message, stage dlq.Stage, _ error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.failures > 0 {
+		d.failures--
+		return errors.New("broker unavailable")
+	}
+	d.published = append(d.published, deadLetter{offset: message.Offset, stage: stage})
+	return nil
+}
+
+type partition struct {
+	committed []kafkaLib.Message
+}
+
+func (p *partition) ID() int {
+	return 0
+}
+
+func (p *partition) CommitMessages(messages ...kafkaLib.Message) error {
+	p.committed = append(p.committed, messages...)
+	return nil
+}
+
+func newTestReader(uc ports.UseCase, publisher deadLetterPublisher) *Reader {
+	return &Reader{
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		validator: validator.New(),
+		uc:        uc,
+		dlq:       publisher,
+		metrics:   metrics.New(),
+		retry: retryPolicy{
+			maxAttempts:    3,
+			initialBackoff: time.Millisecond,
+			maxBackoff:     time.Millisecond,
+			multiplier:     1,
+			pauseInterval:  time.Millisecond,
+		},
+		topic:    testTopic,
+		inFlight: make(chan struct{}, 1),
+	}
+}
+
+func orderMessage(t *testing.T, offset int64) (kafkaLib.Message, string) {
+	t.Helper()
+
+	order := mock.NewMockGenerator().GenerateOrder()
+	for i := range order.Items {
+		order.Items[i].Sale = 10 // the generator may pick 0, which fails validation
+	}
+	value, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("marshal order: %v", err)
+	}
+	return kafkaLib.Message{Topic: testTopic, Offset: offset, Value: value}, order.ID
+}
+
+func TestRetryableErrorThenSuccess(t *testing.T) {
+	message, orderUID := orderMessage(t, 1)
+	uc := &storage{errs: map[string][]error{orderUID: {errTransient, errTransient}}}
+	publisher := &deadLetters{}
+	reader := newTestReader(uc, publisher)
+	committer := &partition{}
+
+	if !reader.processMessage(context.Background(), committer, message) {
+		t.Fatal("processMessage stalled the partition")
+	}
+	if len(uc.created) != 1 || uc.created[0] != orderUID {
+		t.Fatalf("created = %v, want [%s]", uc.created, orderUID)
+	}
+	if len(publisher.published) != 0 {
+		t.Fatalf("dead-lettered %v, want nothing", publisher.published)
+	}
+	if len(committer.committed) != 1 {
+		t.Fatalf("committed %d messages, want 1", len(committer.committed))
+	}
+}
+
+func TestRetriesExhaustedPausesPartition(t *testing.T) {
+	message, orderUID := orderMessage(t, 1)
+	errs := make([]error, 10)
+	for i := range errs {
+		errs[i] = errTransient
+	}
+	uc := &storage{errs: map[string][]error{orderUID: errs}}
+	reader := newTestReader(uc, &deadLetters{})
+
+	if !reader.handleMessage(context.Background(), message) {
+		t.Fatal("handleMessage gave up on a paused message")
+	}
+	if len(uc.created) != 1 {
+		t.Fatalf("created = %v, want the order once storage recovered", uc.created)
+	}
+	if paused := reader.paused.Load(); paused != 0 {
+		t.Fatalf("paused = %d after recovery, want 0", paused)
+	}
+}
+
+func TestNonRetryableErrorGoesToDLQ(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		stage dlq.Stage
+	}{
+		{name: "conflict", err: orderErrs.ErrOrderConflict, stage: dlq.StageConflict},
+		{name: "permanent", err: errors.New("check constraint violated"), stage: dlq.StagePersist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, orderUID := orderMessage(t, 7)
+			uc := &storage{errs: map[string][]error{orderUID: {tt.err}}}
+			// The first publish fails, so the dead-letter write is retried too.
+			publisher := &deadLetters{failures: 1}
+			reader := newTestReader(uc, publisher)
+			committer := &partition{}
+
+			if !reader.processMessage(context.Background(), committer, message) {
+				t.Fatal("processMessage stalled the partition")
+			}
+			if len(uc.created) != 0 {
+				t.Fatalf("created = %v, want nothing", uc.created)
+			}
+			want := []deadLetter{{offset: 7, stage: tt.stage}}
+			if fmt.Sprint(publisher.published) != fmt.Sprint(want) {
+				t.Fatalf("dead-lettered %v, want %v", publisher.published, want)
+			}
+			if len(committer.committed) != 1 {
+				t.Fatalf("committed %d messages, want 1", len(committer.committed))
+			}
+		})
+	}
+}
+
+func TestCancelledPersistLeavesMessageUncommitted(t *testing.T) {
+	message, orderUID := orderMessage(t, 1)
+	errs := make([]error, 100)
+	for i := range errs {
+		errs[i] = errTransient
+	}
+	uc := &storage{errs: map[string][]error{orderUID: errs}}
+	publisher := &deadLetters{}
+	reader := newTestReader(uc, publisher)
+	reader.retry.pauseInterval = time.Hour
+	committer := &partition{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if reader.processMessage(ctx, committer, message) {
+		t.Fatal("processMessage reported a cancelled message as handled")
+	}
+	if len(committer.committed) != 0 || len(publisher.published) != 0 {
+		t.Fatalf("committed %d, dead-lettered %d, want neither", len(committer.committed), len(publisher.published))
+	}
+}
+
+func TestPartialBatchFailure(t *testing.T) {
+	stored, storedUID := orderMessage(t, 10)
+	retried, retriedUID := orderMessage(t, 11)
+	conflicting, _ := orderMessage(t, 12)
+	malformed := kafkaLib.Message{Topic: testTopic, Offset: 13, Value: []byte("{")}
+
+	uc := &storage{
+		errs:      map[string][]error{},
+		batchErrs: []error{nil, errTransient, orderErrs.ErrOrderConflict},
+	}
+	publisher := &deadLetters{}
+	reader := newTestReader(uc, publisher)
+	committer := &partition{}
+
+	batch := []kafkaLib.Message{stored, retried, conflicting, malformed}
+	if !reader.processBatch(context.Background(), committer, batch) {
+		t.Fatal("processBatch stalled the partition")
+	}
+
+	if fmt.Sprint(uc.created) != fmt.Sprint([]string{storedUID, retriedUID}) {
+		t.Fatalf("created = %v, want [%s %s]", uc.created, storedUID, retriedUID)
+	}
+	want := []deadLetter{{offset: 13, stage: dlq.StageDecode}, {offset: 12, stage: dlq.StageConflict}}
+	if fmt.Sprint(publisher.published) != fmt.Sprint(want) {
+		t.Fatalf("dead-lettered %v, want %v", publisher.published, want)
+	}
+	if len(committer.committed) != len(batch) {
+		t.Fatalf("committed %d messages, want %d", len(committer.committed), len(batch))
+	}
+}
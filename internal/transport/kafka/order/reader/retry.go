@@ -0,0 +1,126 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	orderErrs "github.com/D1sordxr/wb-tech-l0/internal/domain/core/order/errors"
+	"github.com/D1sordxr/wb-tech-l0/internal/infrastructure/config"
+	"github.com/D1sordxr/wb-tech-l0/pkg/errtool"
+)
+
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	pauseInterval  time.Duration
+}
+
+func newRetryPolicy(cfg *config.ConsumerRetry) retryPolicy {
+	policy := retryPolicy{
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		multiplier:     cfg.Multiplier,
+		pauseInterval:  cfg.PauseInterval,
+	}
+
+	if policy.maxAttempts < 1 {
+		policy.maxAttempts = 1
+	}
+	if policy.multiplier < 1 {
+		policy.multiplier = 1
+	}
+	if policy.maxBackoff < policy.initialBackoff {
+		policy.maxBackoff = policy.initialBackoff
+	}
+
+	return policy
+}
+
+// backoff returns the delay before the next attempt: exponential growth capped at
+// maxBackoff, with the upper half randomized so replicas do not retry in lockstep.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.initialBackoff) * math.Pow(p.multiplier, float64(attempt-1))
+	if delay > float64(p.maxBackoff) {
+		delay = float64(p.maxBackoff)
+	}
+
+	half := time.Duration(delay / 2)
+	if half <= 0 {
+		return time.Duration(delay)
+	}
+
+	return half + rand.N(half) // #nosec G404 -- jitter does not need a secure source
+}
+
+// persistWithRetry runs persist, retrying transient storage errors with backoff.
+// Once the attempts run out the partition is paused on the current message instead of
+// moving past it; only permanent errors and context cancellation are returned.
+func (r *Reader) persistWithRetry(
+	ctx context.Context,
+	withFields func(args ...any) []any,
+	persist func(ctx context.Context) error,
+) error {
+	paused := false
+	for {
+		for attempt := 1; ; attempt++ {
+			err := persist(ctx)
+			if err == nil {
+				return nil
+			}
+			if ctx.Err() != nil || !isRetryable(err) {
+				return err
+			}
+			if attempt >= r.retry.maxAttempts {
+				r.log.ErrorContext(ctx, "retries exhausted, pausing partition",
+					withFields("attempts", attempt, "pause", r.retry.pauseInterval, "error", err.Error())...,
+				)
+				break
+			}
+
+			backoff := r.retry.backoff(attempt)
+			r.log.WarnContext(ctx, "transient storage error, retrying",
+				withFields("attempt", attempt, "backoff", backoff, "error", err.Error())...,
+			)
+			if !sleep(ctx, backoff) {
+				return ctx.Err()
+			}
+		}
+
+		if !paused {
+			// The partition counts as paused until persist stops failing.
+			paused = true
+			r.paused.Add(1)
+			defer r.paused.Add(-1)
+		}
+		if !sleep(ctx, r.retry.pauseInterval) {
+			return ctx.Err()
+		}
+		r.log.InfoContext(ctx, "resuming paused partition", withFields()...)
+	}
+}
+
+// isRetryable reports whether the storage layer marked err as transient.
+func isRetryable(err error) bool {
+	if errtool.In(err, orderErrs.ErrOrderAlreadyExists) {
+		return false
+	}
+	return errors.Is(err, orderErrs.ErrStorageUnavailable)
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}